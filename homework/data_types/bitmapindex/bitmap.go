@@ -0,0 +1,287 @@
+package bitmapindex
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+)
+
+var ErrCorruptedData = errors.New("corrupted bitmap data")
+
+const (
+	arrayKind  uint8 = 1
+	bitmapKind uint8 = 2
+)
+
+// Bitmap is a compressed set of uint32 row numbers split into 2^16 chunks.
+type Bitmap struct {
+	keys       []uint16
+	containers []container
+}
+
+func NewBitmap(values ...uint32) *Bitmap {
+	bitmap := &Bitmap{}
+	for _, value := range values {
+		bitmap.Add(value)
+	}
+	return bitmap
+}
+
+func (b *Bitmap) Add(value uint32) {
+	key, low := uint16(value>>16), uint16(value)
+	idx, found := slices.BinarySearch(b.keys, key)
+	if !found {
+		b.keys = slices.Insert(b.keys, idx, key)
+		b.containers = slices.Insert(b.containers, idx, container(&arrayContainer{}))
+	}
+	b.containers[idx] = b.containers[idx].add(low)
+}
+
+func (b *Bitmap) Remove(value uint32) {
+	key, low := uint16(value>>16), uint16(value)
+	idx, found := slices.BinarySearch(b.keys, key)
+	if !found {
+		return
+	}
+
+	b.containers[idx] = b.containers[idx].remove(low)
+	if b.containers[idx].cardinality() == 0 {
+		b.keys = slices.Delete(b.keys, idx, idx+1)
+		b.containers = slices.Delete(b.containers, idx, idx+1)
+	}
+}
+
+func (b *Bitmap) Contains(value uint32) bool {
+	idx, found := slices.BinarySearch(b.keys, uint16(value>>16))
+	return found && b.containers[idx].contains(uint16(value))
+}
+
+func (b *Bitmap) Cardinality() int {
+	count := 0
+	for _, c := range b.containers {
+		count += c.cardinality()
+	}
+	return count
+}
+
+func (b *Bitmap) IsEmpty() bool {
+	return len(b.containers) == 0
+}
+
+// ForEach calls action for every value in ascending order until it returns false.
+func (b *Bitmap) ForEach(action func(uint32) bool) {
+	for idx, c := range b.containers {
+		if !c.forEach(uint32(b.keys[idx])<<16, action) {
+			return
+		}
+	}
+}
+
+func (b *Bitmap) ToArray() []uint32 {
+	values := make([]uint32, 0, b.Cardinality())
+	b.ForEach(func(value uint32) bool {
+		values = append(values, value)
+		return true
+	})
+	return values
+}
+
+func (b *Bitmap) Clone() *Bitmap {
+	clone := &Bitmap{
+		keys:       slices.Clone(b.keys),
+		containers: make([]container, len(b.containers)),
+	}
+	for idx, c := range b.containers {
+		clone.containers[idx] = c.clone()
+	}
+	return clone
+}
+
+func (b *Bitmap) And(other *Bitmap) *Bitmap {
+	result := &Bitmap{}
+	for i, j := 0, 0; i < len(b.keys) && j < len(other.keys); {
+		switch {
+		case b.keys[i] < other.keys[j]:
+			i++
+		case b.keys[i] > other.keys[j]:
+			j++
+		default:
+			result.append(b.keys[i], andContainers(b.containers[i], other.containers[j]))
+			i++
+			j++
+		}
+	}
+	return result
+}
+
+func (b *Bitmap) Or(other *Bitmap) *Bitmap {
+	result := &Bitmap{}
+	i, j := 0, 0
+	for i < len(b.keys) && j < len(other.keys) {
+		switch {
+		case b.keys[i] < other.keys[j]:
+			result.append(b.keys[i], b.containers[i].clone())
+			i++
+		case b.keys[i] > other.keys[j]:
+			result.append(other.keys[j], other.containers[j].clone())
+			j++
+		default:
+			result.append(b.keys[i], orContainers(b.containers[i], other.containers[j]))
+			i++
+			j++
+		}
+	}
+	for ; i < len(b.keys); i++ {
+		result.append(b.keys[i], b.containers[i].clone())
+	}
+	for ; j < len(other.keys); j++ {
+		result.append(other.keys[j], other.containers[j].clone())
+	}
+	return result
+}
+
+func (b *Bitmap) AndNot(other *Bitmap) *Bitmap {
+	result := &Bitmap{}
+	j := 0
+	for i := 0; i < len(b.keys); i++ {
+		for j < len(other.keys) && other.keys[j] < b.keys[i] {
+			j++
+		}
+
+		if j < len(other.keys) && other.keys[j] == b.keys[i] {
+			result.append(b.keys[i], andNotContainers(b.containers[i], other.containers[j]))
+		} else {
+			result.append(b.keys[i], b.containers[i].clone())
+		}
+	}
+	return result
+}
+
+func (b *Bitmap) append(key uint16, c container) {
+	if c.cardinality() == 0 {
+		return
+	}
+	b.keys = append(b.keys, key)
+	b.containers = append(b.containers, c)
+}
+
+// WriteTo stores the bitmap as: containers count, then for every container
+// its key, kind, cardinality and either sorted values or 1024 raw words.
+func (b *Bitmap) WriteTo(w io.Writer) (int64, error) {
+	writer := &countingWriter{writer: w}
+	writer.write(uint32(len(b.containers)))
+	for idx, c := range b.containers {
+		writer.write(b.keys[idx])
+		switch c := c.(type) {
+		case *arrayContainer:
+			writer.write(arrayKind)
+			writer.write(uint32(len(c.values)))
+			writer.write(c.values)
+		case *bitmapContainer:
+			writer.write(bitmapKind)
+			writer.write(uint32(c.count))
+			writer.write(c.words)
+		}
+	}
+	return writer.written, writer.err
+}
+
+func (b *Bitmap) ReadFrom(r io.Reader) (int64, error) {
+	reader := &countingReader{reader: r}
+	var count uint32
+	reader.read(&count)
+	if reader.err != nil {
+		return reader.consumed, reader.err
+	}
+	if count > 1<<16 {
+		return reader.consumed, fmt.Errorf("%w: %d containers", ErrCorruptedData, count)
+	}
+
+	// the bitmap keeps its contents when the data is corrupted
+	keys := make([]uint16, 0, count)
+	containers := make([]container, 0, count)
+	for idx := uint32(0); idx < count; idx++ {
+		var key uint16
+		var kind uint8
+		var cardinality uint32
+		reader.read(&key)
+		reader.read(&kind)
+		reader.read(&cardinality)
+		if reader.err != nil {
+			return reader.consumed, reader.err
+		}
+
+		if len(keys) != 0 && keys[len(keys)-1] >= key {
+			return reader.consumed, fmt.Errorf("%w: unordered key %d", ErrCorruptedData, key)
+		}
+
+		var c container
+		switch {
+		case kind == arrayKind && cardinality > 0 && cardinality <= arrayMaxSize:
+			array := &arrayContainer{values: make([]uint16, cardinality)}
+			reader.read(array.values)
+			// lookups search the values, so they have to be sorted and unique
+			for position := 1; reader.err == nil && position < len(array.values); position++ {
+				if array.values[position-1] >= array.values[position] {
+					return reader.consumed, fmt.Errorf("%w: container %d has unordered values", ErrCorruptedData, key)
+				}
+			}
+			c = array
+		case kind == bitmapKind && cardinality > arrayMaxSize:
+			bitmap := &bitmapContainer{words: make([]uint64, bitmapWords)}
+			reader.read(bitmap.words)
+			c = bitmap.normalize()
+		default:
+			return reader.consumed, fmt.Errorf("%w: container %d of kind %d with %d values", ErrCorruptedData, key, kind, cardinality)
+		}
+
+		if reader.err != nil {
+			return reader.consumed, reader.err
+		}
+		if c.cardinality() != int(cardinality) {
+			return reader.consumed, fmt.Errorf("%w: container %d cardinality mismatch", ErrCorruptedData, key)
+		}
+
+		keys = append(keys, key)
+		containers = append(containers, c)
+	}
+
+	b.keys, b.containers = keys, containers
+	return reader.consumed, nil
+}
+
+type countingWriter struct {
+	writer  io.Writer
+	written int64
+	err     error
+}
+
+func (w *countingWriter) write(data any) {
+	if w.err != nil {
+		return
+	}
+	w.err = binary.Write(w.writer, binary.LittleEndian, data)
+	if w.err == nil {
+		w.written += int64(binary.Size(data))
+	}
+}
+
+type countingReader struct {
+	reader   io.Reader
+	consumed int64
+	err      error
+}
+
+func (r *countingReader) read(data any) {
+	if r.err != nil {
+		return
+	}
+	r.err = binary.Read(r.reader, binary.LittleEndian, data)
+	if r.err == nil {
+		r.consumed += int64(binary.Size(data))
+	} else if errors.Is(r.err, io.EOF) || errors.Is(r.err, io.ErrUnexpectedEOF) {
+		r.err = fmt.Errorf("%w: %w", ErrCorruptedData, io.ErrUnexpectedEOF)
+	}
+}
@@ -0,0 +1,226 @@
+package bitmapindex
+
+import (
+	"bytes"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// go test -v -bench=. .
+
+func TestBitmap(t *testing.T) {
+	bitmap := NewBitmap(5, 1, 70000, 1, 1<<32-1)
+	assert.Equal(t, 4, bitmap.Cardinality())
+	assert.Equal(t, []uint32{1, 5, 70000, 1<<32 - 1}, bitmap.ToArray())
+	assert.True(t, bitmap.Contains(70000))
+	assert.False(t, bitmap.Contains(2))
+
+	bitmap.Remove(70000)
+	bitmap.Remove(3)
+	assert.Equal(t, []uint32{1, 5, 1<<32 - 1}, bitmap.ToArray())
+	assert.Len(t, bitmap.containers, 2)
+}
+
+func TestContainerConversion(t *testing.T) {
+	bitmap := &Bitmap{}
+	for value := uint32(0); value <= arrayMaxSize; value++ {
+		bitmap.Add(value * 2)
+	}
+	assert.IsType(t, &bitmapContainer{}, bitmap.containers[0])
+	assert.Equal(t, arrayMaxSize+1, bitmap.Cardinality())
+
+	bitmap.Remove(0)
+	assert.IsType(t, &arrayContainer{}, bitmap.containers[0])
+	assert.Equal(t, arrayMaxSize, bitmap.Cardinality())
+	assert.True(t, bitmap.Contains(2*arrayMaxSize))
+}
+
+func TestSetOperations(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	lhs, rhs := &Bitmap{}, &Bitmap{}
+	lhsSet, rhsSet := map[uint32]bool{}, map[uint32]bool{}
+	for range 50000 {
+		// dense low chunks and sparse high ones to cover both container kinds
+		value := uint32(random.Intn(1 << 17))
+		if random.Intn(4) == 0 {
+			value = random.Uint32()
+		}
+		if random.Intn(2) == 0 {
+			lhs.Add(value)
+			lhsSet[value] = true
+		} else {
+			rhs.Add(value)
+			rhsSet[value] = true
+		}
+	}
+
+	expected := func(keep func(bool, bool) bool) []uint32 {
+		var values []uint32
+		for value := range lhsSet {
+			if keep(true, rhsSet[value]) {
+				values = append(values, value)
+			}
+		}
+		for value := range rhsSet {
+			if !lhsSet[value] && keep(false, true) {
+				values = append(values, value)
+			}
+		}
+		slices.Sort(values)
+		return values
+	}
+
+	assert.Equal(t, expected(func(l, r bool) bool { return l && r }), lhs.And(rhs).ToArray())
+	assert.Equal(t, expected(func(l, r bool) bool { return l || r }), lhs.Or(rhs).ToArray())
+	assert.Equal(t, expected(func(l, r bool) bool { return l && !r }), lhs.AndNot(rhs).ToArray())
+}
+
+func newRestaurants() *Index {
+	index := NewIndex()
+	index.Insert(0, "hookah", "veranda", "alcohol")
+	index.Insert(1, "pets")
+	index.Insert(2, "music")
+	index.Insert(3, "hookah", "pets", "veranda", "alcohol", "music")
+	index.Insert(4, "hookah", "alcohol")
+	return index
+}
+
+func TestQueries(t *testing.T) {
+	tests := map[string]struct {
+		query  Query
+		result []uint32
+	}{
+		"attribute":         {query: Attr("hookah"), result: []uint32{0, 3, 4}},
+		"unknown attribute": {query: Attr("karaoke"), result: []uint32{}},
+		"and":               {query: And(Attr("alcohol"), Attr("music")), result: []uint32{3}},
+		"empty and":         {query: And(), result: []uint32{0, 1, 2, 3, 4}},
+		"or":                {query: Or(Attr("pets"), Attr("music")), result: []uint32{1, 2, 3}},
+		"empty or":          {query: Or(), result: []uint32{}},
+		"not":               {query: Not(Attr("hookah")), result: []uint32{1, 2}},
+		"and not":           {query: AndNot(Attr("alcohol"), Attr("veranda")), result: []uint32{4}},
+		"nested": {
+			query:  Or(And(Attr("hookah"), Not(Attr("veranda"))), AndNot(Attr("pets"), Attr("music"))),
+			result: []uint32{1, 4},
+		},
+	}
+
+	index := newRestaurants()
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.result, index.Search(test.query).ToArray())
+			assert.Equal(t, len(test.result), index.Count(test.query))
+		})
+	}
+}
+
+func TestQueryString(t *testing.T) {
+	query := Or(And(Attr("a"), Not(Attr("b"))), AndNot(Attr("c"), Attr("d")))
+	assert.Equal(t, "((a AND NOT b) OR (c ANDNOT d))", query.String())
+}
+
+func TestIncrementalUpdates(t *testing.T) {
+	index := newRestaurants()
+
+	index.Delete(3)
+	assert.False(t, index.Contains(3))
+	assert.Equal(t, 4, index.Rows())
+	assert.Equal(t, []uint32{0, 4}, index.Search(Attr("hookah")).ToArray())
+	assert.Equal(t, []uint32{1, 2, 4}, index.Search(Not(Attr("veranda"))).ToArray())
+
+	index.Unset(0, "veranda")
+	index.Insert(5, "veranda")
+	assert.Equal(t, []uint32{5}, index.Search(Attr("veranda")).ToArray())
+	assert.Equal(t, 2, index.Cardinality("alcohol"))
+	assert.Equal(t, 0, index.Cardinality("unknown"))
+}
+
+func TestSerialization(t *testing.T) {
+	index := NewIndex()
+	for row := uint32(0); row < 200000; row++ {
+		attributes := []string{"all"}
+		if row%3 == 0 {
+			attributes = append(attributes, "third")
+		}
+		if row%1000 == 0 {
+			attributes = append(attributes, "rare")
+		}
+		index.Insert(row, attributes...)
+	}
+
+	path := filepath.Join(t.TempDir(), "index.bin")
+	file, err := os.Create(path)
+	require.NoError(t, err)
+	written, err := index.WriteTo(file)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(data)), written)
+
+	restored := NewIndex()
+	consumed, err := restored.ReadFrom(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, written, consumed)
+	assert.Equal(t, index.Attributes(), restored.Attributes())
+	assert.Equal(t, index.Rows(), restored.Rows())
+
+	query := AndNot(Attr("third"), Attr("rare"))
+	assert.Equal(t, index.Search(query).ToArray(), restored.Search(query).ToArray())
+
+	_, err = NewIndex().ReadFrom(bytes.NewReader(data[:len(data)/2]))
+	assert.ErrorIs(t, err, ErrCorruptedData)
+
+	var buffer bytes.Buffer
+	_, err = NewBitmap(1, 2, 3).WriteTo(&buffer)
+	require.NoError(t, err)
+	// count, key, kind and cardinality go before the values of the array container
+	const valuesOffset = 4 + 2 + 1 + 4
+	tests := map[string]func(data []byte){
+		"unordered values": func(data []byte) {
+			copy(data[valuesOffset:], []byte{2, 0, 1, 0})
+		},
+		"duplicate values": func(data []byte) {
+			copy(data[valuesOffset:], []byte{1, 0, 1, 0})
+		},
+	}
+
+	for name, corrupt := range tests {
+		t.Run(name, func(t *testing.T) {
+			data := bytes.Clone(buffer.Bytes())
+			corrupt(data)
+			bitmap := NewBitmap(7, 70000)
+			_, err := bitmap.ReadFrom(bytes.NewReader(data))
+			assert.ErrorIs(t, err, ErrCorruptedData)
+			// a failed read keeps the previous contents
+			assert.Equal(t, []uint32{7, 70000}, bitmap.ToArray())
+		})
+	}
+}
+
+func BenchmarkQuery(b *testing.B) {
+	const rows = 1_000_000
+	random := rand.New(rand.NewSource(1))
+	index := NewIndex()
+	for row := uint32(0); row < rows; row++ {
+		var attributes []string
+		for _, attribute := range []string{"hookah", "pets", "veranda", "alcohol", "music"} {
+			if random.Intn(3) == 0 {
+				attributes = append(attributes, attribute)
+			}
+		}
+		index.Insert(row, attributes...)
+	}
+
+	query := Or(And(Attr("hookah"), Attr("alcohol")), AndNot(Attr("music"), Attr("pets")))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = index.Count(query)
+	}
+}
@@ -0,0 +1,255 @@
+package bitmapindex
+
+import (
+	"math/bits"
+	"slices"
+)
+
+// Containers hold the low 16 bits of the values that share the same high 16 bits:
+// sparse chunks are kept as sorted arrays, dense chunks as plain 65536-bit bitmaps.
+const (
+	arrayMaxSize = 4096
+	bitmapWords  = 1 << 16 / 64
+)
+
+type container interface {
+	add(value uint16) container
+	remove(value uint16) container
+	contains(value uint16) bool
+	cardinality() int
+	toBitmap() *bitmapContainer
+	forEach(high uint32, action func(uint32) bool) bool
+	clone() container
+}
+
+type arrayContainer struct {
+	values []uint16
+}
+
+func (c *arrayContainer) add(value uint16) container {
+	idx, found := slices.BinarySearch(c.values, value)
+	if found {
+		return c
+	}
+
+	if len(c.values) >= arrayMaxSize {
+		return c.toBitmap().add(value)
+	}
+
+	c.values = slices.Insert(c.values, idx, value)
+	return c
+}
+
+func (c *arrayContainer) remove(value uint16) container {
+	if idx, found := slices.BinarySearch(c.values, value); found {
+		c.values = slices.Delete(c.values, idx, idx+1)
+	}
+	return c
+}
+
+func (c *arrayContainer) contains(value uint16) bool {
+	_, found := slices.BinarySearch(c.values, value)
+	return found
+}
+
+func (c *arrayContainer) cardinality() int {
+	return len(c.values)
+}
+
+func (c *arrayContainer) toBitmap() *bitmapContainer {
+	bitmap := &bitmapContainer{words: make([]uint64, bitmapWords)}
+	for _, value := range c.values {
+		bitmap.words[value>>6] |= 1 << (value & 63)
+	}
+	bitmap.count = len(c.values)
+	return bitmap
+}
+
+func (c *arrayContainer) forEach(high uint32, action func(uint32) bool) bool {
+	for _, value := range c.values {
+		if !action(high | uint32(value)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *arrayContainer) clone() container {
+	return &arrayContainer{values: slices.Clone(c.values)}
+}
+
+type bitmapContainer struct {
+	words []uint64
+	count int
+}
+
+func (c *bitmapContainer) add(value uint16) container {
+	mask := uint64(1) << (value & 63)
+	if c.words[value>>6]&mask == 0 {
+		c.words[value>>6] |= mask
+		c.count++
+	}
+	return c
+}
+
+func (c *bitmapContainer) remove(value uint16) container {
+	mask := uint64(1) << (value & 63)
+	if c.words[value>>6]&mask != 0 {
+		c.words[value>>6] &^= mask
+		c.count--
+	}
+
+	if c.count <= arrayMaxSize {
+		return c.toArray()
+	}
+	return c
+}
+
+func (c *bitmapContainer) contains(value uint16) bool {
+	return c.words[value>>6]&(1<<(value&63)) != 0
+}
+
+func (c *bitmapContainer) cardinality() int {
+	return c.count
+}
+
+func (c *bitmapContainer) toBitmap() *bitmapContainer {
+	return c
+}
+
+func (c *bitmapContainer) toArray() *arrayContainer {
+	array := &arrayContainer{values: make([]uint16, 0, c.count)}
+	c.forEach(0, func(value uint32) bool {
+		array.values = append(array.values, uint16(value))
+		return true
+	})
+	return array
+}
+
+func (c *bitmapContainer) forEach(high uint32, action func(uint32) bool) bool {
+	for idx, word := range c.words {
+		for word != 0 {
+			offset := bits.TrailingZeros64(word)
+			if !action(high | uint32(idx*64+offset)) {
+				return false
+			}
+			word &= word - 1
+		}
+	}
+	return true
+}
+
+func (c *bitmapContainer) clone() container {
+	return &bitmapContainer{words: slices.Clone(c.words), count: c.count}
+}
+
+// normalize recounts bits after a word-wise operation and
+// falls back to an array when the result became sparse.
+func (c *bitmapContainer) normalize() container {
+	c.count = 0
+	for _, word := range c.words {
+		c.count += bits.OnesCount64(word)
+	}
+
+	if c.count <= arrayMaxSize {
+		return c.toArray()
+	}
+	return c
+}
+
+func andContainers(lhs, rhs container) container {
+	lhsArray, lhsIsArray := lhs.(*arrayContainer)
+	rhsArray, rhsIsArray := rhs.(*arrayContainer)
+	switch {
+	case lhsIsArray && rhsIsArray:
+		return intersectArrays(lhsArray, rhsArray)
+	case lhsIsArray:
+		return filterArray(lhsArray, rhs, true)
+	case rhsIsArray:
+		return filterArray(rhsArray, lhs, true)
+	}
+
+	lhsBitmap, rhsBitmap := lhs.toBitmap(), rhs.toBitmap()
+	result := &bitmapContainer{words: make([]uint64, bitmapWords)}
+	for idx := range result.words {
+		result.words[idx] = lhsBitmap.words[idx] & rhsBitmap.words[idx]
+	}
+	return result.normalize()
+}
+
+func orContainers(lhs, rhs container) container {
+	lhsArray, lhsIsArray := lhs.(*arrayContainer)
+	rhsArray, rhsIsArray := rhs.(*arrayContainer)
+	if lhsIsArray && rhsIsArray && len(lhsArray.values)+len(rhsArray.values) <= arrayMaxSize {
+		return unionArrays(lhsArray, rhsArray)
+	}
+
+	lhsBitmap, rhsBitmap := lhs.toBitmap(), rhs.toBitmap()
+	result := &bitmapContainer{words: make([]uint64, bitmapWords)}
+	for idx := range result.words {
+		result.words[idx] = lhsBitmap.words[idx] | rhsBitmap.words[idx]
+	}
+	return result.normalize()
+}
+
+func andNotContainers(lhs, rhs container) container {
+	if lhsArray, ok := lhs.(*arrayContainer); ok {
+		return filterArray(lhsArray, rhs, false)
+	}
+
+	lhsBitmap, rhsBitmap := lhs.toBitmap(), rhs.toBitmap()
+	result := &bitmapContainer{words: make([]uint64, bitmapWords)}
+	for idx := range result.words {
+		result.words[idx] = lhsBitmap.words[idx] &^ rhsBitmap.words[idx]
+	}
+	return result.normalize()
+}
+
+func intersectArrays(lhs, rhs *arrayContainer) container {
+	result := &arrayContainer{values: make([]uint16, 0, min(len(lhs.values), len(rhs.values)))}
+	for i, j := 0, 0; i < len(lhs.values) && j < len(rhs.values); {
+		switch {
+		case lhs.values[i] < rhs.values[j]:
+			i++
+		case lhs.values[i] > rhs.values[j]:
+			j++
+		default:
+			result.values = append(result.values, lhs.values[i])
+			i++
+			j++
+		}
+	}
+	return result
+}
+
+func unionArrays(lhs, rhs *arrayContainer) container {
+	result := &arrayContainer{values: make([]uint16, 0, len(lhs.values)+len(rhs.values))}
+	i, j := 0, 0
+	for i < len(lhs.values) && j < len(rhs.values) {
+		switch {
+		case lhs.values[i] < rhs.values[j]:
+			result.values = append(result.values, lhs.values[i])
+			i++
+		case lhs.values[i] > rhs.values[j]:
+			result.values = append(result.values, rhs.values[j])
+			j++
+		default:
+			result.values = append(result.values, lhs.values[i])
+			i++
+			j++
+		}
+	}
+	result.values = append(result.values, lhs.values[i:]...)
+	result.values = append(result.values, rhs.values[j:]...)
+	return result
+}
+
+func filterArray(array *arrayContainer, other container, keep bool) container {
+	result := &arrayContainer{values: make([]uint16, 0, len(array.values))}
+	for _, value := range array.values {
+		if other.contains(value) == keep {
+			result.values = append(result.values, value)
+		}
+	}
+	return result
+}
@@ -0,0 +1,259 @@
+package bitmapindex
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// Index keeps one bitmap per attribute plus a bitmap of all live rows,
+// which is needed to answer NOT queries.
+type Index struct {
+	rows       *Bitmap
+	attributes map[string]*Bitmap
+}
+
+func NewIndex() *Index {
+	return &Index{
+		rows:       &Bitmap{},
+		attributes: make(map[string]*Bitmap),
+	}
+}
+
+// Insert adds the row if needed and sets the given attributes on it.
+func (i *Index) Insert(row uint32, attributes ...string) {
+	i.rows.Add(row)
+	for _, attribute := range attributes {
+		bitmap, found := i.attributes[attribute]
+		if !found {
+			bitmap = &Bitmap{}
+			i.attributes[attribute] = bitmap
+		}
+		bitmap.Add(row)
+	}
+}
+
+// Unset clears the given attributes on the row, the row itself stays.
+func (i *Index) Unset(row uint32, attributes ...string) {
+	for _, attribute := range attributes {
+		if bitmap, found := i.attributes[attribute]; found {
+			bitmap.Remove(row)
+		}
+	}
+}
+
+func (i *Index) Delete(row uint32) {
+	i.rows.Remove(row)
+	for _, bitmap := range i.attributes {
+		bitmap.Remove(row)
+	}
+}
+
+func (i *Index) Contains(row uint32) bool {
+	return i.rows.Contains(row)
+}
+
+func (i *Index) Rows() int {
+	return i.rows.Cardinality()
+}
+
+func (i *Index) Attributes() []string {
+	attributes := make([]string, 0, len(i.attributes))
+	for attribute := range i.attributes {
+		attributes = append(attributes, attribute)
+	}
+	slices.Sort(attributes)
+	return attributes
+}
+
+func (i *Index) Cardinality(attribute string) int {
+	if bitmap, found := i.attributes[attribute]; found {
+		return bitmap.Cardinality()
+	}
+	return 0
+}
+
+func (i *Index) Search(query Query) *Bitmap {
+	return query.evaluate(i)
+}
+
+func (i *Index) Count(query Query) int {
+	return i.Search(query).Cardinality()
+}
+
+func (i *Index) attribute(name string) *Bitmap {
+	if bitmap, found := i.attributes[name]; found {
+		return bitmap
+	}
+	return &Bitmap{}
+}
+
+// WriteTo stores the rows bitmap followed by every attribute name and bitmap.
+func (i *Index) WriteTo(w io.Writer) (int64, error) {
+	written, err := i.rows.WriteTo(w)
+	if err != nil {
+		return written, err
+	}
+
+	writer := &countingWriter{writer: w, written: written}
+	writer.write(uint32(len(i.attributes)))
+	for _, attribute := range i.Attributes() {
+		writer.write(uint16(len(attribute)))
+		writer.write([]byte(attribute))
+		if writer.err != nil {
+			break
+		}
+
+		written, err := i.attributes[attribute].WriteTo(w)
+		writer.written += written
+		writer.err = err
+	}
+	return writer.written, writer.err
+}
+
+func (i *Index) ReadFrom(r io.Reader) (int64, error) {
+	rows := &Bitmap{}
+	consumed, err := rows.ReadFrom(r)
+	if err != nil {
+		return consumed, err
+	}
+
+	reader := &countingReader{reader: r, consumed: consumed}
+	var count uint32
+	reader.read(&count)
+
+	attributes := make(map[string]*Bitmap)
+	for idx := uint32(0); idx < count && reader.err == nil; idx++ {
+		var length uint16
+		reader.read(&length)
+		name := make([]byte, length)
+		reader.read(name)
+		if reader.err != nil {
+			break
+		}
+
+		bitmap := &Bitmap{}
+		consumed, err := bitmap.ReadFrom(r)
+		reader.consumed += consumed
+		if err != nil {
+			return reader.consumed, fmt.Errorf("attribute %q: %w", name, err)
+		}
+		if bitmap.AndNot(rows).Cardinality() != 0 {
+			return reader.consumed, fmt.Errorf("%w: attribute %q references deleted rows", ErrCorruptedData, name)
+		}
+		attributes[string(name)] = bitmap
+	}
+
+	if reader.err != nil {
+		return reader.consumed, reader.err
+	}
+
+	i.rows, i.attributes = rows, attributes
+	return reader.consumed, nil
+}
+
+type Query interface {
+	evaluate(index *Index) *Bitmap
+	String() string
+}
+
+type attributeQuery string
+
+// Attr matches rows that have the attribute set.
+func Attr(name string) Query {
+	return attributeQuery(name)
+}
+
+func (q attributeQuery) evaluate(index *Index) *Bitmap {
+	return index.attribute(string(q)).Clone()
+}
+
+func (q attributeQuery) String() string {
+	return string(q)
+}
+
+type andQuery []Query
+
+// And without arguments matches every row.
+func And(queries ...Query) Query {
+	return andQuery(queries)
+}
+
+func (q andQuery) evaluate(index *Index) *Bitmap {
+	if len(q) == 0 {
+		return index.rows.Clone()
+	}
+
+	result := q[0].evaluate(index)
+	for _, query := range q[1:] {
+		if result.IsEmpty() {
+			break
+		}
+		result = result.And(query.evaluate(index))
+	}
+	return result
+}
+
+func (q andQuery) String() string {
+	return join("AND", q)
+}
+
+type orQuery []Query
+
+// Or without arguments matches nothing.
+func Or(queries ...Query) Query {
+	return orQuery(queries)
+}
+
+func (q orQuery) evaluate(index *Index) *Bitmap {
+	result := &Bitmap{}
+	for _, query := range q {
+		result = result.Or(query.evaluate(index))
+	}
+	return result
+}
+
+func (q orQuery) String() string {
+	return join("OR", q)
+}
+
+type notQuery struct {
+	query Query
+}
+
+func Not(query Query) Query {
+	return notQuery{query: query}
+}
+
+func (q notQuery) evaluate(index *Index) *Bitmap {
+	return index.rows.AndNot(q.query.evaluate(index))
+}
+
+func (q notQuery) String() string {
+	return "NOT " + q.query.String()
+}
+
+type andNotQuery struct {
+	lhs, rhs Query
+}
+
+func AndNot(lhs, rhs Query) Query {
+	return andNotQuery{lhs: lhs, rhs: rhs}
+}
+
+func (q andNotQuery) evaluate(index *Index) *Bitmap {
+	return q.lhs.evaluate(index).AndNot(q.rhs.evaluate(index))
+}
+
+func (q andNotQuery) String() string {
+	return join("ANDNOT", []Query{q.lhs, q.rhs})
+}
+
+func join(operator string, queries []Query) string {
+	parts := make([]string, len(queries))
+	for idx, query := range queries {
+		parts[idx] = query.String()
+	}
+	return "(" + strings.Join(parts, " "+operator+" ") + ")"
+}
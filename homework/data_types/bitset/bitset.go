@@ -0,0 +1,248 @@
+package bitset
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/bits"
+	"slices"
+)
+
+var ErrInvalidData = errors.New("invalid bitset data")
+
+const wordSize = 64
+
+// BitSet grows on demand, the zero value is an empty set.
+type BitSet struct {
+	words []uint64
+}
+
+func New(capacity int) *BitSet {
+	return &BitSet{words: make([]uint64, wordsFor(capacity))}
+}
+
+func FromIndexes(indexes ...int) *BitSet {
+	set := &BitSet{}
+	for _, index := range indexes {
+		set.Set(index)
+	}
+	return set
+}
+
+func (s *BitSet) IsSet(index int) bool {
+	checkIndex(index)
+	word := index / wordSize
+	return word < len(s.words) && s.words[word]&(1<<(index%wordSize)) != 0
+}
+
+func (s *BitSet) Set(index int) {
+	checkIndex(index)
+	s.grow(index)
+	s.words[index/wordSize] |= 1 << (index % wordSize)
+}
+
+func (s *BitSet) Inverse(index int) {
+	checkIndex(index)
+	s.grow(index)
+	s.words[index/wordSize] ^= 1 << (index % wordSize)
+}
+
+func (s *BitSet) Reset(index int) {
+	checkIndex(index)
+	if word := index / wordSize; word < len(s.words) {
+		s.words[word] &^= 1 << (index % wordSize)
+	}
+}
+
+// Len returns the number of bits the set can hold without growing.
+func (s *BitSet) Len() int {
+	return len(s.words) * wordSize
+}
+
+func (s *BitSet) PopCount() int {
+	count := 0
+	for _, word := range s.words {
+		count += bits.OnesCount64(word)
+	}
+	return count
+}
+
+func (s *BitSet) IsEmpty() bool {
+	for _, word := range s.words {
+		if word != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *BitSet) Clone() *BitSet {
+	return &BitSet{words: slices.Clone(s.words)}
+}
+
+func (s *BitSet) Equal(other *BitSet) bool {
+	return slices.Equal(s.trimmed(), other.trimmed())
+}
+
+func (s *BitSet) Union(other *BitSet) *BitSet {
+	result := &BitSet{words: make([]uint64, max(len(s.words), len(other.words)))}
+	copy(result.words, s.words)
+	for idx, word := range other.words {
+		result.words[idx] |= word
+	}
+	return result
+}
+
+func (s *BitSet) Intersection(other *BitSet) *BitSet {
+	result := &BitSet{words: make([]uint64, min(len(s.words), len(other.words)))}
+	for idx := range result.words {
+		result.words[idx] = s.words[idx] & other.words[idx]
+	}
+	return result
+}
+
+func (s *BitSet) Difference(other *BitSet) *BitSet {
+	result := s.Clone()
+	for idx := 0; idx < min(len(s.words), len(other.words)); idx++ {
+		result.words[idx] &^= other.words[idx]
+	}
+	return result
+}
+
+func (s *BitSet) SymmetricDifference(other *BitSet) *BitSet {
+	result := &BitSet{words: make([]uint64, max(len(s.words), len(other.words)))}
+	copy(result.words, s.words)
+	for idx, word := range other.words {
+		result.words[idx] ^= word
+	}
+	return result
+}
+
+// NextSet returns the first set bit at or after index.
+func (s *BitSet) NextSet(index int) (int, bool) {
+	checkIndex(index)
+	word := index / wordSize
+	if word >= len(s.words) {
+		return 0, false
+	}
+
+	current := s.words[word] >> (index % wordSize)
+	if current != 0 {
+		return index + bits.TrailingZeros64(current), true
+	}
+
+	for word++; word < len(s.words); word++ {
+		if s.words[word] != 0 {
+			return word*wordSize + bits.TrailingZeros64(s.words[word]), true
+		}
+	}
+	return 0, false
+}
+
+// ForEach calls action for every set bit in ascending order until it returns false.
+func (s *BitSet) ForEach(action func(int) bool) {
+	for idx, word := range s.words {
+		for word != 0 {
+			if !action(idx*wordSize + bits.TrailingZeros64(word)) {
+				return
+			}
+			word &= word - 1 // drop the lowest set bit
+		}
+	}
+}
+
+func (s *BitSet) Indexes() []int {
+	indexes := make([]int, 0, s.PopCount())
+	s.ForEach(func(index int) bool {
+		indexes = append(indexes, index)
+		return true
+	})
+	return indexes
+}
+
+// Rank returns the number of set bits strictly before index.
+func (s *BitSet) Rank(index int) int {
+	checkIndex(index)
+	word := index / wordSize
+	if word >= len(s.words) {
+		return s.PopCount()
+	}
+
+	count := 0
+	for _, w := range s.words[:word] {
+		count += bits.OnesCount64(w)
+	}
+	mask := uint64(1)<<(index%wordSize) - 1
+	return count + bits.OnesCount64(s.words[word]&mask)
+}
+
+// Select returns the index of the set bit with the given zero-based rank.
+func (s *BitSet) Select(rank int) (int, bool) {
+	if rank < 0 {
+		return 0, false
+	}
+
+	for idx, word := range s.words {
+		count := bits.OnesCount64(word)
+		if rank >= count {
+			rank -= count
+			continue
+		}
+
+		for ; rank > 0; rank-- {
+			word &= word - 1
+		}
+		return idx*wordSize + bits.TrailingZeros64(word), true
+	}
+	return 0, false
+}
+
+// MarshalBinary encodes the words in little endian order without trailing zero words.
+func (s *BitSet) MarshalBinary() ([]byte, error) {
+	words := s.trimmed()
+	data := make([]byte, 0, len(words)*8)
+	for _, word := range words {
+		data = binary.LittleEndian.AppendUint64(data, word)
+	}
+	return data, nil
+}
+
+func (s *BitSet) UnmarshalBinary(data []byte) error {
+	if len(data)%8 != 0 {
+		return fmt.Errorf("%w: length %d is not a multiple of 8", ErrInvalidData, len(data))
+	}
+
+	s.words = make([]uint64, len(data)/8)
+	for idx := range s.words {
+		s.words[idx] = binary.LittleEndian.Uint64(data[idx*8:])
+	}
+	return nil
+}
+
+func (s *BitSet) String() string {
+	return fmt.Sprint(s.Indexes())
+}
+
+func (s *BitSet) grow(index int) {
+	if word := index / wordSize; word >= len(s.words) {
+		s.words = append(s.words, make([]uint64, word-len(s.words)+1)...)
+	}
+}
+
+func (s *BitSet) trimmed() []uint64 {
+	last := len(s.words)
+	for last > 0 && s.words[last-1] == 0 {
+		last--
+	}
+	return s.words[:last]
+}
+
+func wordsFor(capacity int) int {
+	return (capacity + wordSize - 1) / wordSize
+}
+
+func checkIndex(index int) {
+	if index < 0 {
+		panic(fmt.Sprintf("bitset: negative index %d", index))
+	}
+}
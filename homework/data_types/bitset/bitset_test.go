@@ -0,0 +1,208 @@
+package bitset
+
+import (
+	"encoding"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// go test -v -bench=. .
+
+var _ encoding.BinaryMarshaler = (*BitSet)(nil)
+var _ encoding.BinaryUnmarshaler = (*BitSet)(nil)
+
+func TestBitOperations(t *testing.T) {
+	var set BitSet
+	assert.False(t, set.IsSet(1000))
+
+	set.Set(3)
+	set.Set(130)
+	assert.True(t, set.IsSet(3))
+	assert.True(t, set.IsSet(130))
+	assert.False(t, set.IsSet(4))
+	assert.Equal(t, 192, set.Len())
+
+	set.Inverse(3)
+	set.Inverse(4)
+	assert.False(t, set.IsSet(3))
+	assert.True(t, set.IsSet(4))
+
+	set.Reset(130)
+	set.Reset(5000)
+	assert.Equal(t, []int{4}, set.Indexes())
+	assert.Equal(t, 1, set.PopCount())
+
+	assert.Panics(t, func() { set.Set(-1) })
+}
+
+func TestSetOperations(t *testing.T) {
+	lhs := FromIndexes(1, 2, 64, 200)
+	rhs := FromIndexes(2, 64, 65)
+
+	tests := map[string]struct {
+		result *BitSet
+		values []int
+	}{
+		"union":                {result: lhs.Union(rhs), values: []int{1, 2, 64, 65, 200}},
+		"intersection":         {result: lhs.Intersection(rhs), values: []int{2, 64}},
+		"difference":           {result: lhs.Difference(rhs), values: []int{1, 200}},
+		"reverse difference":   {result: rhs.Difference(lhs), values: []int{65}},
+		"symmetric difference": {result: lhs.SymmetricDifference(rhs), values: []int{1, 65, 200}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.values, test.result.Indexes())
+		})
+	}
+
+	assert.Equal(t, []int{1, 2, 64, 200}, lhs.Indexes())
+	assert.True(t, FromIndexes(1).Equal(New(1000).Union(FromIndexes(1))))
+	assert.True(t, lhs.Intersection(&BitSet{}).IsEmpty())
+}
+
+func TestIteration(t *testing.T) {
+	set := FromIndexes(0, 63, 64, 127, 1000)
+
+	var visited []int
+	set.ForEach(func(index int) bool {
+		visited = append(visited, index)
+		return index < 64
+	})
+	assert.Equal(t, []int{0, 63, 64}, visited)
+
+	tests := map[int]struct {
+		next  int
+		found bool
+	}{
+		0:    {next: 0, found: true},
+		1:    {next: 63, found: true},
+		65:   {next: 127, found: true},
+		128:  {next: 1000, found: true},
+		1001: {found: false},
+		5000: {found: false},
+	}
+	for from, test := range tests {
+		next, found := set.NextSet(from)
+		assert.Equal(t, test.found, found, from)
+		assert.Equal(t, test.next, next, from)
+	}
+}
+
+func TestRankSelect(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	set := New(10000)
+	var indexes []int
+	for index := 0; index < 10000; index++ {
+		if random.Intn(7) == 0 {
+			set.Set(index)
+			indexes = append(indexes, index)
+		}
+	}
+
+	for rank, index := range indexes {
+		assert.Equal(t, rank, set.Rank(index))
+		assert.Equal(t, rank+1, set.Rank(index+1))
+
+		selected, found := set.Select(rank)
+		assert.True(t, found)
+		assert.Equal(t, index, selected)
+	}
+
+	assert.Equal(t, len(indexes), set.Rank(1<<20))
+	_, found := set.Select(len(indexes))
+	assert.False(t, found)
+	_, found = set.Select(-1)
+	assert.False(t, found)
+}
+
+func TestBinaryMarshaling(t *testing.T) {
+	set := FromIndexes(1, 64, 1000)
+	set.Set(5000)
+	set.Reset(5000)
+
+	data, err := set.MarshalBinary()
+	require.NoError(t, err)
+	assert.Len(t, data, 16*8)
+
+	var restored BitSet
+	require.NoError(t, restored.UnmarshalBinary(data))
+	assert.True(t, set.Equal(&restored))
+	assert.Equal(t, "[1 64 1000]", restored.String())
+
+	assert.ErrorIs(t, restored.UnmarshalBinary([]byte{1, 2, 3}), ErrInvalidData)
+}
+
+const benchmarkSize = 1 << 16
+
+func BenchmarkBitSetSet(b *testing.B) {
+	set := New(benchmarkSize)
+	for i := 0; i < b.N; i++ {
+		set.Set(i % benchmarkSize)
+	}
+}
+
+func BenchmarkMapSet(b *testing.B) {
+	set := make(map[int]bool, benchmarkSize)
+	for i := 0; i < b.N; i++ {
+		set[i%benchmarkSize] = true
+	}
+}
+
+func BenchmarkBitSetIsSet(b *testing.B) {
+	set := New(benchmarkSize)
+	for index := 0; index < benchmarkSize; index += 3 {
+		set.Set(index)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = set.IsSet(i % benchmarkSize)
+	}
+}
+
+func BenchmarkMapIsSet(b *testing.B) {
+	set := make(map[int]bool, benchmarkSize)
+	for index := 0; index < benchmarkSize; index += 3 {
+		set[index] = true
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = set[i%benchmarkSize]
+	}
+}
+
+func BenchmarkBitSetIteration(b *testing.B) {
+	set := New(benchmarkSize)
+	for index := 0; index < benchmarkSize; index += 3 {
+		set.Set(index)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		count := 0
+		set.ForEach(func(int) bool {
+			count++
+			return true
+		})
+	}
+}
+
+func BenchmarkMapIteration(b *testing.B) {
+	set := make(map[int]bool, benchmarkSize)
+	for index := 0; index < benchmarkSize; index += 3 {
+		set[index] = true
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		count := 0
+		for range set {
+			count++
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package endian
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"unsafe"
+)
+
+var ErrOutOfBounds = errors.New("offset out of bounds")
+
+type Number interface {
+	~int8 | ~int16 | ~int32 | ~int64 | ~int |
+		~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uint | ~uintptr |
+		~float32 | ~float64
+}
+
+var littleEndian bool
+
+func init() {
+	var number int16 = 0x0001
+	pointer := (*int8)(unsafe.Pointer(&number))
+	littleEndian = *pointer == 1
+}
+
+func IsLittleEndian() bool {
+	return littleEndian
+}
+
+func IsBigEndian() bool {
+	return !littleEndian
+}
+
+// Swap reverses the byte order of any fixed-size number, floats included.
+func Swap[T Number](number T) T {
+	pointer := unsafe.Pointer(&number)
+	switch unsafe.Sizeof(number) {
+	case 2:
+		*(*uint16)(pointer) = bits.ReverseBytes16(*(*uint16)(pointer))
+	case 4:
+		*(*uint32)(pointer) = bits.ReverseBytes32(*(*uint32)(pointer))
+	case 8:
+		*(*uint64)(pointer) = bits.ReverseBytes64(*(*uint64)(pointer))
+	}
+	return number
+}
+
+// SwapSlice reverses the byte order of every element in place.
+func SwapSlice[T Number](numbers []T) {
+	for idx := range numbers {
+		numbers[idx] = Swap(numbers[idx])
+	}
+}
+
+func ToBigEndian[T Number](number T) T {
+	if littleEndian {
+		return Swap(number)
+	}
+	return number
+}
+
+func ToLittleEndian[T Number](number T) T {
+	if littleEndian {
+		return number
+	}
+	return Swap(number)
+}
+
+func PutBE[T Number](buffer []byte, offset int, number T) error {
+	return put(buffer, offset, ToBigEndian(number))
+}
+
+func PutLE[T Number](buffer []byte, offset int, number T) error {
+	return put(buffer, offset, ToLittleEndian(number))
+}
+
+func ReadBE[T Number](buffer []byte, offset int) (T, error) {
+	number, err := read[T](buffer, offset)
+	return ToBigEndian(number), err
+}
+
+func ReadLE[T Number](buffer []byte, offset int) (T, error) {
+	number, err := read[T](buffer, offset)
+	return ToLittleEndian(number), err
+}
+
+func put[T Number](buffer []byte, offset int, number T) error {
+	source := bytesOf(&number)
+	if err := checkBounds(buffer, offset, len(source)); err != nil {
+		return err
+	}
+
+	copy(buffer[offset:], source)
+	return nil
+}
+
+func read[T Number](buffer []byte, offset int) (T, error) {
+	var number T
+	destination := bytesOf(&number)
+	if err := checkBounds(buffer, offset, len(destination)); err != nil {
+		return 0, err
+	}
+
+	copy(destination, buffer[offset:])
+	return number, nil
+}
+
+func checkBounds(buffer []byte, offset, size int) error {
+	if offset < 0 || offset > len(buffer)-size {
+		return fmt.Errorf("%w: offset %d, size %d, length %d", ErrOutOfBounds, offset, size, len(buffer))
+	}
+	return nil
+}
+
+func bytesOf[T Number](number *T) []byte {
+	return unsafe.Slice((*byte)(unsafe.Pointer(number)), unsafe.Sizeof(*number))
+}
@@ -0,0 +1,198 @@
+package endian
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// go test -v -bench=. -fuzz=FuzzUint64 .
+
+func TestHostEndianness(t *testing.T) {
+	var buffer [2]byte
+	binary.NativeEndian.PutUint16(buffer[:], 0x0001)
+	assert.Equal(t, buffer[0] == 1, IsLittleEndian())
+	assert.NotEqual(t, IsLittleEndian(), IsBigEndian())
+}
+
+func TestSwap(t *testing.T) {
+	assert.Equal(t, uint16(0x0201), Swap(uint16(0x0102)))
+	assert.Equal(t, uint32(0x04030201), Swap(uint32(0x01020304)))
+	assert.Equal(t, uint64(0x0807060504030201), Swap(uint64(0x0102030405060708)))
+	assert.Equal(t, int8(-5), Swap(int8(-5)))
+	assert.Equal(t, int16(-2), Swap(int16(-257)))
+	assert.Equal(t, int32(-1), Swap(int32(-1)))
+	assert.Equal(t, Swap(math.Float32bits(1.5)), math.Float32bits(Swap(float32(1.5))))
+	assert.Equal(t, 2.5, Swap(Swap(2.5)))
+}
+
+func TestSwapSlice(t *testing.T) {
+	numbers := []uint32{0x01020304, 0x00FF00FF, 0xFFFFFFFF}
+	SwapSlice(numbers)
+	assert.Equal(t, []uint32{0x04030201, 0xFF00FF00, 0xFFFFFFFF}, numbers)
+
+	array := [2]int16{0x0102, -1}
+	SwapSlice(array[:])
+	assert.Equal(t, [2]int16{0x0201, -1}, array)
+}
+
+func TestPutAndRead(t *testing.T) {
+	buffer := make([]byte, 12)
+
+	assert.NoError(t, PutBE(buffer, 0, uint32(0x01020304)))
+	assert.NoError(t, PutLE(buffer, 4, uint32(0x01020304)))
+	assert.NoError(t, PutBE(buffer, 8, int16(-2)))
+	assert.Equal(t, []byte{1, 2, 3, 4, 4, 3, 2, 1, 0xFF, 0xFE, 0, 0}, buffer)
+
+	beNumber, err := ReadBE[uint32](buffer, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(0x01020304), beNumber)
+
+	leNumber, err := ReadLE[uint32](buffer, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(0x01020304), leNumber)
+
+	signed, err := ReadBE[int16](buffer, 8)
+	assert.NoError(t, err)
+	assert.Equal(t, int16(-2), signed)
+
+	assert.NoError(t, PutLE(buffer, 4, 3.25))
+	float, err := ReadLE[float64](buffer, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, 3.25, float)
+}
+
+func TestBounds(t *testing.T) {
+	tests := map[string]struct {
+		length int
+		offset int
+		failed bool
+	}{
+		"exact fit":         {length: 4, offset: 0},
+		"fit with offset":   {length: 6, offset: 2},
+		"negative offset":   {length: 4, offset: -1, failed: true},
+		"offset past end":   {length: 4, offset: 5, failed: true},
+		"value crosses end": {length: 4, offset: 1, failed: true},
+		"empty buffer":      {length: 0, offset: 0, failed: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			buffer := make([]byte, test.length)
+			putErr := PutBE(buffer, test.offset, uint32(1))
+			_, readErr := ReadLE[uint32](buffer, test.offset)
+			if test.failed {
+				assert.ErrorIs(t, putErr, ErrOutOfBounds)
+				assert.ErrorIs(t, readErr, ErrOutOfBounds)
+			} else {
+				assert.NoError(t, putErr)
+				assert.NoError(t, readErr)
+			}
+		})
+	}
+}
+
+func TestZeroAllocations(t *testing.T) {
+	buffer := make([]byte, 8)
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = PutBE(buffer, 0, uint64(0x0102030405060708))
+		_, _ = ReadLE[float64](buffer, 0)
+		_ = Swap(int32(-1))
+	})
+	assert.Zero(t, allocs)
+}
+
+func FuzzUint16(f *testing.F) {
+	f.Add(uint16(0x0102))
+	f.Fuzz(func(t *testing.T, number uint16) {
+		expected := make([]byte, 2)
+		buffer := make([]byte, 2)
+
+		binary.BigEndian.PutUint16(expected, number)
+		assert.NoError(t, PutBE(buffer, 0, number))
+		assert.Equal(t, expected, buffer)
+
+		binary.LittleEndian.PutUint16(expected, number)
+		assert.NoError(t, PutLE(buffer, 0, number))
+		assert.Equal(t, expected, buffer)
+	})
+}
+
+func FuzzUint64(f *testing.F) {
+	f.Add(uint64(0x0102030405060708), 3)
+	f.Fuzz(func(t *testing.T, number uint64, offset int) {
+		if offset < 0 || offset > 16 {
+			t.Skip()
+		}
+
+		buffer := make([]byte, offset+8)
+		binary.BigEndian.PutUint64(buffer[offset:], number)
+		result, err := ReadBE[uint64](buffer, offset)
+		assert.NoError(t, err)
+		assert.Equal(t, number, result)
+
+		binary.LittleEndian.PutUint64(buffer[offset:], number)
+		result, err = ReadLE[uint64](buffer, offset)
+		assert.NoError(t, err)
+		assert.Equal(t, number, result)
+	})
+}
+
+func FuzzSigned(f *testing.F) {
+	f.Add(int32(-1), int64(math.MinInt64))
+	f.Fuzz(func(t *testing.T, small int32, large int64) {
+		buffer := make([]byte, 12)
+		assert.NoError(t, PutBE(buffer, 0, small))
+		assert.NoError(t, PutLE(buffer, 4, large))
+		assert.Equal(t, uint32(small), binary.BigEndian.Uint32(buffer))
+		assert.Equal(t, uint64(large), binary.LittleEndian.Uint64(buffer[4:]))
+	})
+}
+
+func FuzzFloat(f *testing.F) {
+	f.Add(math.Pi, float32(math.E))
+	f.Fuzz(func(t *testing.T, large float64, small float32) {
+		buffer := make([]byte, 12)
+		assert.NoError(t, PutBE(buffer, 0, large))
+		assert.NoError(t, PutLE(buffer, 8, small))
+		assert.Equal(t, math.Float64bits(large), binary.BigEndian.Uint64(buffer))
+		assert.Equal(t, math.Float32bits(small), binary.LittleEndian.Uint32(buffer[8:]))
+	})
+}
+
+func BenchmarkSwap(b *testing.B) {
+	b.ReportAllocs()
+	number := uint64(0x0102030405060708)
+	for i := 0; i < b.N; i++ {
+		number = Swap(number)
+	}
+	_ = number
+}
+
+func BenchmarkPutBE(b *testing.B) {
+	b.ReportAllocs()
+	buffer := make([]byte, 8)
+	for i := 0; i < b.N; i++ {
+		_ = PutBE(buffer, 0, uint64(i))
+	}
+}
+
+func BenchmarkBinaryPutUint64(b *testing.B) {
+	b.ReportAllocs()
+	buffer := make([]byte, 8)
+	for i := 0; i < b.N; i++ {
+		binary.BigEndian.PutUint64(buffer, uint64(i))
+	}
+}
+
+func BenchmarkReadLE(b *testing.B) {
+	b.ReportAllocs()
+	buffer := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+	var result float64
+	for i := 0; i < b.N; i++ {
+		result, _ = ReadLE[float64](buffer, 0)
+	}
+	_ = result
+}
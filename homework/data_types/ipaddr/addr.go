@@ -0,0 +1,321 @@
+package ipaddr
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+const (
+	ipv4Len    = 32
+	ipv6Len    = 128
+	octetsLen  = 4
+	groupsLen  = 8
+	groupWidth = 16
+)
+
+// ParseError points at the part of the input that could not be parsed,
+// Position is 1-based within its Segment kind ("octet", "group", ...).
+type ParseError struct {
+	Input    string
+	Segment  string
+	Position int
+	Value    string
+	Reason   string
+}
+
+func (e *ParseError) Error() string {
+	if e.Segment == "" {
+		return fmt.Sprintf("invalid address %q: %s", e.Input, e.Reason)
+	}
+	return fmt.Sprintf("invalid address %q: %s %d %q: %s", e.Input, e.Segment, e.Position, e.Value, e.Reason)
+}
+
+// Addr is an IPv4 or IPv6 address, the zero value is not a valid address.
+type Addr struct {
+	value  uint128
+	bitLen int
+}
+
+func AddrFromUint32(address uint32) Addr {
+	return Addr{value: uint128{low: uint64(address)}, bitLen: ipv4Len}
+}
+
+func AddrFromUint128(high, low uint64) Addr {
+	return Addr{value: uint128{high: high, low: low}, bitLen: ipv6Len}
+}
+
+func AddrFromBigInt(number *big.Int) (Addr, error) {
+	if number.Sign() < 0 || number.BitLen() > ipv6Len {
+		return Addr{}, fmt.Errorf("number %s does not fit into IPv6 address", number)
+	}
+
+	low := new(big.Int).And(number, new(big.Int).SetUint64(^uint64(0)))
+	high := new(big.Int).Rsh(number, 64)
+	return AddrFromUint128(high.Uint64(), low.Uint64()), nil
+}
+
+func ParseAddr(input string) (Addr, error) {
+	if strings.Contains(input, ":") {
+		return ParseIPv6(input)
+	}
+	return ParseIPv4(input)
+}
+
+func MustParseAddr(input string) Addr {
+	addr, err := ParseAddr(input)
+	if err != nil {
+		panic(err)
+	}
+	return addr
+}
+
+func ParseIPv4(input string) (Addr, error) {
+	address, err := parseIPv4(input, input)
+	if err != nil {
+		return Addr{}, err
+	}
+	return AddrFromUint32(address), nil
+}
+
+func parseIPv4(input, segment string) (uint32, error) {
+	octets := strings.Split(segment, ".")
+	if len(octets) != octetsLen {
+		return 0, &ParseError{Input: input, Reason: fmt.Sprintf("expected %d octets, got %d", octetsLen, len(octets))}
+	}
+
+	var result uint32
+	for idx, octet := range octets {
+		reason := ""
+		number, err := strconv.ParseUint(octet, 10, 8)
+		switch {
+		case octet == "":
+			reason = "empty octet"
+		case strings.TrimLeft(octet, "0123456789") != "":
+			reason = "not a decimal number"
+		case len(octet) > 1 && octet[0] == '0':
+			reason = "leading zero"
+		case err != nil:
+			reason = "value out of range 0-255"
+		}
+
+		if reason != "" {
+			return 0, &ParseError{Input: input, Segment: "octet", Position: idx + 1, Value: octet, Reason: reason}
+		}
+
+		bitOffset := (octetsLen - idx - 1) * 8
+		result |= uint32(number) << bitOffset
+	}
+
+	return result, nil
+}
+
+func ParseIPv6(input string) (Addr, error) {
+	if strings.Contains(input, "%") {
+		return Addr{}, &ParseError{Input: input, Reason: "zones are not supported"}
+	}
+
+	head, tail, ellipsis := strings.Cut(input, "::")
+	if ellipsis && strings.Contains(tail, "::") {
+		return Addr{}, &ParseError{Input: input, Reason: `"::" may appear only once`}
+	}
+
+	headGroups, err := parseGroups(input, head, 0, !ellipsis)
+	if err != nil {
+		return Addr{}, err
+	}
+
+	var tailGroups []uint16
+	if ellipsis {
+		if tailGroups, err = parseGroups(input, tail, len(headGroups), true); err != nil {
+			return Addr{}, err
+		}
+	}
+
+	count := len(headGroups) + len(tailGroups)
+	switch {
+	case !ellipsis && count != groupsLen:
+		return Addr{}, &ParseError{Input: input, Reason: fmt.Sprintf("expected %d groups, got %d", groupsLen, count)}
+	case ellipsis && count >= groupsLen:
+		return Addr{}, &ParseError{Input: input, Reason: fmt.Sprintf(`too many groups around "::": %d`, count)}
+	}
+
+	groups := make([]uint16, groupsLen)
+	copy(groups, headGroups)
+	copy(groups[groupsLen-len(tailGroups):], tailGroups)
+
+	var value uint128
+	for idx, group := range groups {
+		if idx < groupsLen/2 {
+			value.high = value.high<<groupWidth | uint64(group)
+		} else {
+			value.low = value.low<<groupWidth | uint64(group)
+		}
+	}
+	return Addr{value: value, bitLen: ipv6Len}, nil
+}
+
+// parseGroups parses colon separated hex groups, the last group
+// may be an embedded IPv4 address when allowIPv4 is set.
+func parseGroups(input, part string, offset int, allowIPv4 bool) ([]uint16, error) {
+	if part == "" {
+		return nil, nil
+	}
+
+	segments := strings.Split(part, ":")
+	groups := make([]uint16, 0, len(segments)+1)
+	for idx, segment := range segments {
+		position := offset + idx + 1
+		if allowIPv4 && idx == len(segments)-1 && strings.Contains(segment, ".") {
+			address, err := parseIPv4(input, segment)
+			if err != nil {
+				return nil, err
+			}
+			groups = append(groups, uint16(address>>16), uint16(address))
+			break
+		}
+
+		reason := ""
+		number, err := strconv.ParseUint(segment, 16, 16)
+		switch {
+		case segment == "":
+			reason = "empty group"
+		case len(segment) > 4:
+			reason = "more than 4 hex digits"
+		case err != nil:
+			reason = "not a hex number"
+		}
+
+		if reason != "" {
+			return nil, &ParseError{Input: input, Segment: "group", Position: position, Value: segment, Reason: reason}
+		}
+		groups = append(groups, uint16(number))
+	}
+	return groups, nil
+}
+
+func (a Addr) IsValid() bool {
+	return a.bitLen != 0
+}
+
+func (a Addr) Is4() bool {
+	return a.bitLen == ipv4Len
+}
+
+func (a Addr) Is6() bool {
+	return a.bitLen == ipv6Len
+}
+
+func (a Addr) BitLen() int {
+	return a.bitLen
+}
+
+// Uint32 returns the IPv4 address as a number, IPv6 addresses are truncated.
+func (a Addr) Uint32() uint32 {
+	return uint32(a.value.low)
+}
+
+func (a Addr) Uint128() (high, low uint64) {
+	return a.value.high, a.value.low
+}
+
+func (a Addr) BigInt() *big.Int {
+	number := new(big.Int).SetUint64(a.value.high)
+	number.Lsh(number, 64)
+	return number.Or(number, new(big.Int).SetUint64(a.value.low))
+}
+
+// Compare orders IPv4 addresses before IPv6 ones.
+func (a Addr) Compare(other Addr) int {
+	switch {
+	case a.bitLen < other.bitLen:
+		return -1
+	case a.bitLen > other.bitLen:
+		return 1
+	}
+	return a.value.compare(other.value)
+}
+
+// Next returns the following address or an invalid one on overflow.
+func (a Addr) Next() Addr {
+	if a.value == lowOnes(a.bitLen) {
+		return Addr{}
+	}
+	value, _ := a.value.add(uint128{low: 1})
+	return Addr{value: value, bitLen: a.bitLen}
+}
+
+// Prev returns the preceding address or an invalid one on underflow.
+func (a Addr) Prev() Addr {
+	if a.value == (uint128{}) {
+		return Addr{}
+	}
+	value, _ := a.value.sub(uint128{low: 1})
+	return Addr{value: value, bitLen: a.bitLen}
+}
+
+func (a Addr) String() string {
+	switch a.bitLen {
+	case ipv4Len:
+		return formatIPv4(uint32(a.value.low))
+	case ipv6Len:
+		return a.formatIPv6()
+	}
+	return "invalid IP"
+}
+
+func formatIPv4(address uint32) string {
+	var builder strings.Builder
+	for idx := 0; idx < octetsLen; idx++ {
+		if idx != 0 {
+			builder.WriteByte('.')
+		}
+		bitOffset := (octetsLen - idx - 1) * 8
+		builder.WriteString(strconv.Itoa(int(address >> bitOffset & 0xFF)))
+	}
+	return builder.String()
+}
+
+// formatIPv6 follows RFC 5952: the longest run of two or more zero groups
+// collapses into "::" and IPv4-mapped addresses keep the dotted tail.
+func (a Addr) formatIPv6() string {
+	if a.value.high == 0 && a.value.low>>32 == 0xFFFF {
+		return "::ffff:" + formatIPv4(uint32(a.value.low))
+	}
+
+	var groups [groupsLen]uint16
+	for idx := range groups {
+		if idx < groupsLen/2 {
+			groups[idx] = uint16(a.value.high >> ((groupsLen/2 - idx - 1) * groupWidth))
+		} else {
+			groups[idx] = uint16(a.value.low >> ((groupsLen - idx - 1) * groupWidth))
+		}
+	}
+
+	zerosStart, zerosLen := -1, 1
+	for idx := 0; idx < groupsLen; idx++ {
+		end := idx
+		for end < groupsLen && groups[end] == 0 {
+			end++
+		}
+		if end-idx > zerosLen {
+			zerosStart, zerosLen = idx, end-idx
+		}
+		idx = end
+	}
+
+	var builder strings.Builder
+	for idx := 0; idx < groupsLen; idx++ {
+		if idx == zerosStart {
+			builder.WriteString("::")
+			idx += zerosLen - 1
+			continue
+		}
+		if idx != 0 && idx != zerosStart+zerosLen {
+			builder.WriteByte(':')
+		}
+		builder.WriteString(strconv.FormatUint(uint64(groups[idx]), 16))
+	}
+	return builder.String()
+}
@@ -0,0 +1,283 @@
+package ipaddr
+
+import (
+	"errors"
+	"math/big"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// go test -v -fuzz=FuzzParseAddr .
+
+func TestParseIPv4(t *testing.T) {
+	tests := map[string]struct {
+		address string
+		result  uint32
+	}{
+		"zero address":      {address: "0.0.0.0", result: 0},
+		"broadcast address": {address: "255.255.255.255", result: 0xFFFFFFFF},
+		"lesson address":    {address: "255.255.6.0", result: 0xFFFF0600},
+		"private address":   {address: "192.168.1.10", result: 0xC0A8010A},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			addr, err := ParseAddr(test.address)
+			require.NoError(t, err)
+			assert.True(t, addr.Is4())
+			assert.Equal(t, test.result, addr.Uint32())
+			assert.Equal(t, test.address, addr.String())
+			assert.Equal(t, addr, AddrFromUint32(test.result))
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := map[string]struct {
+		address  string
+		segment  string
+		position int
+		value    string
+		message  string
+	}{
+		"octet out of range": {
+			address: "1.2.300.4", segment: "octet", position: 3, value: "300",
+			message: `invalid address "1.2.300.4": octet 3 "300": value out of range 0-255`,
+		},
+		"empty octet": {
+			address: "1..3.4", segment: "octet", position: 2, value: "",
+		},
+		"leading zero": {
+			address: "1.2.3.04", segment: "octet", position: 4, value: "04",
+		},
+		"sign in octet": {
+			address: "+1.2.3.4", segment: "octet", position: 1, value: "+1",
+		},
+		"wrong octets count": {
+			address: "1.2.3", message: `invalid address "1.2.3": expected 4 octets, got 3`,
+		},
+		"bad hex group": {
+			address: "2001:db8::g:1", segment: "group", position: 3, value: "g",
+		},
+		"long hex group": {
+			address: "12345::", segment: "group", position: 1, value: "12345",
+		},
+		"embedded ipv4 octet": {
+			address: "::ffff:1.2.3.999", segment: "octet", position: 4, value: "999",
+		},
+		"double ellipsis": {
+			address: "1::2::3",
+		},
+		"too few groups": {
+			address: "1:2:3:4:5:6:7",
+		},
+		"too many groups": {
+			address: "1:2:3:4:5:6:7::8",
+		},
+		"zone": {
+			address: "fe80::1%eth0",
+		},
+		"prefix length": {
+			address: "10.0.0.0/33", segment: "prefix length", position: 1, value: "33",
+		},
+		"prefix octet": {
+			address: "10.0.256.0/24", segment: "octet", position: 3, value: "256",
+			message: `invalid address "10.0.256.0/24": octet 3 "256": value out of range 0-255`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var err error
+			if strings.Contains(test.address, "/") {
+				_, err = ParsePrefix(test.address)
+			} else {
+				_, err = ParseAddr(test.address)
+			}
+
+			var parseErr *ParseError
+			require.True(t, errors.As(err, &parseErr), "%v", err)
+			assert.Equal(t, test.address, parseErr.Input)
+			assert.Equal(t, test.segment, parseErr.Segment)
+			assert.Equal(t, test.position, parseErr.Position)
+			assert.Equal(t, test.value, parseErr.Value)
+			if test.message != "" {
+				assert.EqualError(t, err, test.message)
+			}
+		})
+	}
+}
+
+func TestIPv6Formatting(t *testing.T) {
+	tests := map[string]struct {
+		address string
+		result  string
+	}{
+		"unspecified":     {address: "::", result: "::"},
+		"loopback":        {address: "0:0:0:0:0:0:0:1", result: "::1"},
+		"leading zeros":   {address: "2001:0DB8:0000:0000:0000:0000:0000:0001", result: "2001:db8::1"},
+		"longest run":     {address: "2001:0:0:1:0:0:0:1", result: "2001:0:0:1::1"},
+		"first of equal":  {address: "2001:db8:0:0:1:0:0:1", result: "2001:db8::1:0:0:1"},
+		"single zero":     {address: "2001:db8:0:1:1:1:1:1", result: "2001:db8:0:1:1:1:1:1"},
+		"trailing zeros":  {address: "fe80::", result: "fe80::"},
+		"ipv4 mapped":     {address: "::FFFF:10.0.0.1", result: "::ffff:10.0.0.1"},
+		"embedded ipv4":   {address: "64:ff9b::192.0.2.33", result: "64:ff9b::c000:221"},
+		"single ellipsis": {address: "1:2:3:4:5:6:7::", result: "1:2:3:4:5:6:7:0"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			addr, err := ParseAddr(test.address)
+			require.NoError(t, err)
+			assert.True(t, addr.Is6())
+			assert.Equal(t, test.result, addr.String())
+		})
+	}
+}
+
+func TestIntegerConversion(t *testing.T) {
+	addr := MustParseAddr("2001:db8::ff00:42:8329")
+	high, low := addr.Uint128()
+	assert.Equal(t, uint64(0x20010db800000000), high)
+	assert.Equal(t, uint64(0x0000ff0000428329), low)
+	assert.Equal(t, addr, AddrFromUint128(high, low))
+
+	number, ok := new(big.Int).SetString("42540766411282592856904265327123268393", 10)
+	require.True(t, ok)
+	assert.Equal(t, number, addr.BigInt())
+
+	restored, err := AddrFromBigInt(number)
+	require.NoError(t, err)
+	assert.Equal(t, addr, restored)
+
+	_, err = AddrFromBigInt(new(big.Int).Lsh(big.NewInt(1), 128))
+	assert.Error(t, err)
+}
+
+func TestNextPrev(t *testing.T) {
+	assert.Equal(t, "10.0.1.0", MustParseAddr("10.0.0.255").Next().String())
+	assert.Equal(t, "::1:0:0", MustParseAddr("::ffff:ffff").Next().String())
+	assert.Equal(t, "::ffff:ffff", MustParseAddr("::1:0:0").Prev().String())
+	assert.False(t, MustParseAddr("255.255.255.255").Next().IsValid())
+	assert.False(t, MustParseAddr("::").Prev().IsValid())
+	assert.Equal(t, -1, MustParseAddr("255.255.255.255").Compare(MustParseAddr("::")))
+}
+
+func TestPrefix(t *testing.T) {
+	prefix := MustParsePrefix("192.168.1.77/24")
+	assert.Equal(t, "192.168.1.0/24", prefix.String())
+	assert.Equal(t, "192.168.1.0", prefix.First().String())
+	assert.Equal(t, "192.168.1.255", prefix.Last().String())
+	assert.True(t, prefix.Contains(MustParseAddr("192.168.1.200")))
+	assert.False(t, prefix.Contains(MustParseAddr("192.168.2.1")))
+	assert.False(t, prefix.Contains(MustParseAddr("::ffff:192.168.1.1")))
+	assert.True(t, prefix.ContainsPrefix(MustParsePrefix("192.168.1.128/25")))
+	assert.False(t, prefix.ContainsPrefix(MustParsePrefix("192.168.0.0/16")))
+	assert.True(t, prefix.Overlaps(MustParsePrefix("192.168.0.0/16")))
+
+	all := MustParsePrefix("::/0")
+	assert.True(t, all.Contains(MustParseAddr("2001:db8::1")))
+	assert.Equal(t, "ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff", all.Last().String())
+	assert.Equal(t, "2001:db8::/32", MustParsePrefix("2001:db8:1:2::/32").String())
+}
+
+func TestSubnets(t *testing.T) {
+	var subnets []string
+	err := MustParsePrefix("10.0.0.0/22").Subnets(24, func(subnet Prefix) bool {
+		subnets = append(subnets, subnet.String())
+		return true
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.0/24", "10.0.1.0/24", "10.0.2.0/24", "10.0.3.0/24"}, subnets)
+
+	subnets = nil
+	err = MustParsePrefix("::/0").Subnets(2, func(subnet Prefix) bool {
+		subnets = append(subnets, subnet.String())
+		return true
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"::/2", "4000::/2", "8000::/2", "c000::/2"}, subnets)
+
+	count := 0
+	err = MustParsePrefix("2001:db8::/32").Subnets(64, func(Prefix) bool {
+		count++
+		return count < 3
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	assert.Error(t, MustParsePrefix("10.0.0.0/24").Subnets(16, func(Prefix) bool { return true }))
+}
+
+func TestSplitRange(t *testing.T) {
+	tests := map[string]struct {
+		first, last string
+		result      []string
+	}{
+		"single address": {
+			first: "10.0.0.1", last: "10.0.0.1", result: []string{"10.0.0.1/32"},
+		},
+		"aligned block": {
+			first: "10.0.0.0", last: "10.0.0.255", result: []string{"10.0.0.0/24"},
+		},
+		"unaligned range": {
+			first: "10.0.0.5", last: "10.0.0.20",
+			result: []string{"10.0.0.5/32", "10.0.0.6/31", "10.0.0.8/29", "10.0.0.16/30", "10.0.0.20/32"},
+		},
+		"whole ipv4 space": {
+			first: "0.0.0.0", last: "255.255.255.255", result: []string{"0.0.0.0/0"},
+		},
+		"ipv6 tail": {
+			first: "2001:db8::1", last: "2001:db8::4",
+			result: []string{"2001:db8::1/128", "2001:db8::2/127", "2001:db8::4/128"},
+		},
+		"top of ipv6 space": {
+			first: "ffff:ffff:ffff:ffff:ffff:ffff:ffff:fffe", last: "ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff",
+			result: []string{"ffff:ffff:ffff:ffff:ffff:ffff:ffff:fffe/127"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			prefixes, err := SplitRange(MustParseAddr(test.first), MustParseAddr(test.last))
+			require.NoError(t, err)
+			result := make([]string, len(prefixes))
+			for idx, prefix := range prefixes {
+				result[idx] = prefix.String()
+			}
+			assert.Equal(t, test.result, result)
+		})
+	}
+
+	_, err := SplitRange(MustParseAddr("10.0.0.2"), MustParseAddr("10.0.0.1"))
+	assert.ErrorIs(t, err, ErrInvalidRange)
+	_, err = SplitRange(MustParseAddr("10.0.0.1"), MustParseAddr("::1"))
+	assert.ErrorIs(t, err, ErrFamilyMismatch)
+}
+
+func FuzzParseAddr(f *testing.F) {
+	for _, seed := range []string{"1.2.3.4", "::", "2001:db8::1", "::ffff:1.2.3.4", "1:2:3:4:5:6:7:8", "01.2.3.4", "1::2::3"} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		if strings.Contains(input, "%") {
+			t.Skip()
+		}
+
+		expected, expectedErr := netip.ParseAddr(input)
+		addr, err := ParseAddr(input)
+		if expectedErr != nil {
+			assert.Error(t, err)
+			return
+		}
+
+		require.NoError(t, err)
+		assert.Equal(t, expected.String(), addr.String())
+		assert.Equal(t, expected.Is4(), addr.Is4())
+	})
+}
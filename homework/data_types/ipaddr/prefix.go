@@ -0,0 +1,157 @@
+package ipaddr
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrFamilyMismatch = errors.New("address families do not match")
+	ErrInvalidRange   = errors.New("invalid address range")
+)
+
+// Prefix is a CIDR block, its address always has the host bits cleared.
+type Prefix struct {
+	addr Addr
+	bits int
+}
+
+func PrefixFrom(addr Addr, bits int) (Prefix, error) {
+	if !addr.IsValid() || bits < 0 || bits > addr.bitLen {
+		return Prefix{}, fmt.Errorf("invalid prefix length %d for %s", bits, addr)
+	}
+
+	addr.value = addr.value.and(netmask(addr.bitLen, bits))
+	return Prefix{addr: addr, bits: bits}, nil
+}
+
+func ParsePrefix(input string) (Prefix, error) {
+	address, length, found := strings.Cut(input, "/")
+	if !found {
+		return Prefix{}, &ParseError{Input: input, Reason: "missing prefix length"}
+	}
+
+	addr, err := ParseAddr(address)
+	if err != nil {
+		var parseErr *ParseError
+		if errors.As(err, &parseErr) {
+			parseErr.Input = input
+		}
+		return Prefix{}, err
+	}
+
+	bits, err := strconv.Atoi(length)
+	invalidDigits := strings.TrimLeft(length, "0123456789") != "" || (len(length) > 1 && length[0] == '0')
+	if err != nil || invalidDigits || bits > addr.bitLen {
+		reason := fmt.Sprintf("must be between 0 and %d", addr.bitLen)
+		return Prefix{}, &ParseError{Input: input, Segment: "prefix length", Position: 1, Value: length, Reason: reason}
+	}
+
+	return PrefixFrom(addr, bits)
+}
+
+func MustParsePrefix(input string) Prefix {
+	prefix, err := ParsePrefix(input)
+	if err != nil {
+		panic(err)
+	}
+	return prefix
+}
+
+func (p Prefix) IsValid() bool {
+	return p.addr.IsValid()
+}
+
+func (p Prefix) Addr() Addr {
+	return p.addr
+}
+
+func (p Prefix) Bits() int {
+	return p.bits
+}
+
+// First and Last return the lowest and the highest addresses of the block.
+func (p Prefix) First() Addr {
+	return p.addr
+}
+
+func (p Prefix) Last() Addr {
+	addr := p.addr
+	addr.value = addr.value.or(lowOnes(addr.bitLen - p.bits))
+	return addr
+}
+
+func (p Prefix) Contains(addr Addr) bool {
+	return p.addr.bitLen == addr.bitLen && addr.value.and(netmask(p.addr.bitLen, p.bits)) == p.addr.value
+}
+
+// ContainsPrefix reports whether other is the same block or a subnet of p.
+func (p Prefix) ContainsPrefix(other Prefix) bool {
+	return p.bits <= other.bits && p.Contains(other.addr)
+}
+
+func (p Prefix) Overlaps(other Prefix) bool {
+	return p.ContainsPrefix(other) || other.ContainsPrefix(p)
+}
+
+func (p Prefix) String() string {
+	if !p.IsValid() {
+		return "invalid Prefix"
+	}
+	return p.addr.String() + "/" + strconv.Itoa(p.bits)
+}
+
+// Subnets calls action for every subnet of the given length in ascending
+// order until it returns false.
+func (p Prefix) Subnets(bits int, action func(Prefix) bool) error {
+	if bits < p.bits || bits > p.addr.bitLen {
+		return fmt.Errorf("subnet length %d is out of range %d-%d", bits, p.bits, p.addr.bitLen)
+	}
+
+	last := p.Last()
+	step := lowOnes(p.addr.bitLen - bits)
+	step, _ = step.add(uint128{low: 1})
+	subnet := Prefix{addr: p.addr, bits: bits}
+	for {
+		if !action(subnet) || subnet.Last() == last {
+			return nil
+		}
+		subnet.addr.value, _ = subnet.addr.value.add(step)
+	}
+}
+
+// SplitRange returns the minimal list of prefixes that exactly cover
+// addresses from first to last inclusive.
+func SplitRange(first, last Addr) ([]Prefix, error) {
+	if first.bitLen != last.bitLen || !first.IsValid() {
+		return nil, ErrFamilyMismatch
+	}
+	if first.Compare(last) > 0 {
+		return nil, fmt.Errorf("%w: %s is after %s", ErrInvalidRange, first, last)
+	}
+
+	var prefixes []Prefix
+	for {
+		hostBits := min(first.value.trailingZeros(), first.bitLen)
+		for {
+			end, overflow := first.value.add(lowOnes(hostBits))
+			if !overflow && end.compare(last.value) <= 0 {
+				break
+			}
+			hostBits--
+		}
+
+		prefix := Prefix{addr: first, bits: first.bitLen - hostBits}
+		prefixes = append(prefixes, prefix)
+		if prefix.Last() == last {
+			return prefixes, nil
+		}
+		first = prefix.Last().Next()
+	}
+}
+
+func netmask(bitLen, bits int) uint128 {
+	return lowOnes(bitLen - bits).not().and(lowOnes(bitLen))
+}
@@ -0,0 +1,65 @@
+package ipaddr
+
+import "math/bits"
+
+// uint128 keeps both address families in one representation:
+// IPv4 addresses occupy the lowest 32 bits.
+type uint128 struct {
+	high, low uint64
+}
+
+func (u uint128) and(other uint128) uint128 {
+	return uint128{high: u.high & other.high, low: u.low & other.low}
+}
+
+func (u uint128) or(other uint128) uint128 {
+	return uint128{high: u.high | other.high, low: u.low | other.low}
+}
+
+func (u uint128) not() uint128 {
+	return uint128{high: ^u.high, low: ^u.low}
+}
+
+func (u uint128) add(other uint128) (uint128, bool) {
+	low, carry := bits.Add64(u.low, other.low, 0)
+	high, carry := bits.Add64(u.high, other.high, carry)
+	return uint128{high: high, low: low}, carry != 0
+}
+
+func (u uint128) sub(other uint128) (uint128, bool) {
+	low, borrow := bits.Sub64(u.low, other.low, 0)
+	high, borrow := bits.Sub64(u.high, other.high, borrow)
+	return uint128{high: high, low: low}, borrow != 0
+}
+
+func (u uint128) compare(other uint128) int {
+	switch {
+	case u.high < other.high:
+		return -1
+	case u.high > other.high:
+		return 1
+	case u.low < other.low:
+		return -1
+	case u.low > other.low:
+		return 1
+	}
+	return 0
+}
+
+func (u uint128) trailingZeros() int {
+	if u.low != 0 {
+		return bits.TrailingZeros64(u.low)
+	}
+	return 64 + bits.TrailingZeros64(u.high)
+}
+
+// lowOnes returns a value with the count lowest bits set.
+func lowOnes(count int) uint128 {
+	switch {
+	case count >= 128:
+		return uint128{high: ^uint64(0), low: ^uint64(0)}
+	case count >= 64:
+		return uint128{high: 1<<(count-64) - 1, low: ^uint64(0)}
+	}
+	return uint128{low: 1<<count - 1}
+}
@@ -0,0 +1,328 @@
+package iptrie
+
+import (
+	"math/bits"
+	"sync"
+	"sync/atomic"
+
+	"golang_course/homework/data_types/ipaddr"
+)
+
+// Table is a Patricia trie of IP prefixes with longest-prefix-match lookups.
+// Readers work on immutable snapshots without locks, writers copy the nodes
+// on the modified path and publish a new root when they are done.
+type Table[V any] struct {
+	mutex      sync.Mutex
+	generation uint64
+	snapshot   atomic.Pointer[Snapshot[V]]
+}
+
+type Snapshot[V any] struct {
+	roots [2]*node[V]
+	size  int
+}
+
+type node[V any] struct {
+	prefix     ipaddr.Prefix
+	value      V
+	hasValue   bool
+	children   [2]*node[V]
+	generation uint64
+}
+
+func New[V any]() *Table[V] {
+	return &Table[V]{}
+}
+
+func (t *Table[V]) Snapshot() *Snapshot[V] {
+	if snapshot := t.snapshot.Load(); snapshot != nil {
+		return snapshot
+	}
+	return &Snapshot[V]{}
+}
+
+func (t *Table[V]) Lookup(addr ipaddr.Addr) (ipaddr.Prefix, V, bool) {
+	return t.Snapshot().Lookup(addr)
+}
+
+func (t *Table[V]) Get(prefix ipaddr.Prefix) (V, bool) {
+	return t.Snapshot().Get(prefix)
+}
+
+func (t *Table[V]) Len() int {
+	return t.Snapshot().Len()
+}
+
+// Insert stores the value and reports whether an existing one was replaced.
+func (t *Table[V]) Insert(prefix ipaddr.Prefix, value V) bool {
+	var replaced bool
+	t.Update(func(batch *Batch[V]) {
+		replaced = batch.Insert(prefix, value)
+	})
+	return replaced
+}
+
+func (t *Table[V]) Delete(prefix ipaddr.Prefix) bool {
+	var deleted bool
+	t.Update(func(batch *Batch[V]) {
+		deleted = batch.Delete(prefix)
+	})
+	return deleted
+}
+
+// Update applies several changes at once: nodes created inside the batch are
+// modified in place and readers see either none or all of the changes.
+func (t *Table[V]) Update(action func(batch *Batch[V])) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.generation++
+	current := t.Snapshot()
+	batch := &Batch[V]{
+		generation: t.generation,
+		roots:      current.roots,
+		size:       current.size,
+	}
+
+	action(batch)
+	t.snapshot.Store(&Snapshot[V]{roots: batch.roots, size: batch.size})
+}
+
+type Batch[V any] struct {
+	generation uint64
+	roots      [2]*node[V]
+	size       int
+}
+
+// Insert ignores invalid prefixes.
+func (b *Batch[V]) Insert(prefix ipaddr.Prefix, value V) bool {
+	if !prefix.IsValid() {
+		return false
+	}
+
+	root := &b.roots[family(prefix.Addr())]
+	var replaced bool
+	*root = b.insert(*root, prefix, value, &replaced)
+	if !replaced {
+		b.size++
+	}
+	return replaced
+}
+
+func (b *Batch[V]) Delete(prefix ipaddr.Prefix) bool {
+	if !prefix.IsValid() {
+		return false
+	}
+
+	root := &b.roots[family(prefix.Addr())]
+	var deleted bool
+	*root = b.delete(*root, prefix, &deleted)
+	if deleted {
+		b.size--
+	}
+	return deleted
+}
+
+func (b *Batch[V]) insert(current *node[V], prefix ipaddr.Prefix, value V, replaced *bool) *node[V] {
+	if current == nil {
+		return &node[V]{prefix: prefix, value: value, hasValue: true, generation: b.generation}
+	}
+
+	common := commonBits(current.prefix, prefix)
+	switch {
+	case common == current.prefix.Bits() && common == prefix.Bits():
+		current = b.own(current)
+		*replaced = current.hasValue
+		current.value, current.hasValue = value, true
+		return current
+	case common == current.prefix.Bits():
+		current = b.own(current)
+		side := bit(prefix.Addr(), common)
+		current.children[side] = b.insert(current.children[side], prefix, value, replaced)
+		return current
+	case common == prefix.Bits():
+		parent := &node[V]{prefix: prefix, value: value, hasValue: true, generation: b.generation}
+		parent.children[bit(current.prefix.Addr(), common)] = current
+		return parent
+	}
+
+	glue, _ := ipaddr.PrefixFrom(prefix.Addr(), common)
+	parent := &node[V]{prefix: glue, generation: b.generation}
+	parent.children[bit(prefix.Addr(), common)] = &node[V]{prefix: prefix, value: value, hasValue: true, generation: b.generation}
+	parent.children[bit(current.prefix.Addr(), common)] = current
+	return parent
+}
+
+func (b *Batch[V]) delete(current *node[V], prefix ipaddr.Prefix, deleted *bool) *node[V] {
+	if current == nil || !current.prefix.ContainsPrefix(prefix) {
+		return current
+	}
+
+	if current.prefix.Bits() == prefix.Bits() {
+		if !current.hasValue {
+			return current
+		}
+		*deleted = true
+		if current.children[0] != nil && current.children[1] != nil {
+			current = b.own(current)
+			var empty V
+			current.value, current.hasValue = empty, false
+			return current
+		}
+		return onlyChild(current)
+	}
+
+	side := bit(prefix.Addr(), current.prefix.Bits())
+	child := b.delete(current.children[side], prefix, deleted)
+	if child == current.children[side] {
+		return current
+	}
+
+	if !current.hasValue && child == nil {
+		// glue nodes with a single child are not needed anymore
+		return current.children[1-side]
+	}
+
+	current = b.own(current)
+	current.children[side] = child
+	return current
+}
+
+func (b *Batch[V]) own(current *node[V]) *node[V] {
+	if current.generation == b.generation {
+		return current
+	}
+	clone := *current
+	clone.generation = b.generation
+	return &clone
+}
+
+func (s *Snapshot[V]) Len() int {
+	return s.size
+}
+
+// Lookup returns the longest stored prefix that contains the address.
+func (s *Snapshot[V]) Lookup(addr ipaddr.Addr) (ipaddr.Prefix, V, bool) {
+	var best *node[V]
+	for current := s.roots[family(addr)]; current != nil && current.prefix.Contains(addr); {
+		if current.hasValue {
+			best = current
+		}
+		if current.prefix.Bits() == addr.BitLen() {
+			break
+		}
+		current = current.children[bit(addr, current.prefix.Bits())]
+	}
+
+	if best == nil {
+		var empty V
+		return ipaddr.Prefix{}, empty, false
+	}
+	return best.prefix, best.value, true
+}
+
+func (s *Snapshot[V]) Get(prefix ipaddr.Prefix) (V, bool) {
+	current := s.roots[family(prefix.Addr())]
+	for current != nil && current.prefix.ContainsPrefix(prefix) {
+		if current.prefix.Bits() == prefix.Bits() {
+			return current.value, current.hasValue
+		}
+		current = current.children[bit(prefix.Addr(), current.prefix.Bits())]
+	}
+
+	var empty V
+	return empty, false
+}
+
+// Covering calls action for every stored prefix that contains the given one,
+// from the shortest to the longest, until it returns false.
+func (s *Snapshot[V]) Covering(prefix ipaddr.Prefix, action func(ipaddr.Prefix, V) bool) {
+	current := s.roots[family(prefix.Addr())]
+	for current != nil && current.prefix.ContainsPrefix(prefix) {
+		if current.hasValue && !action(current.prefix, current.value) {
+			return
+		}
+		if current.prefix.Bits() == prefix.Bits() {
+			return
+		}
+		current = current.children[bit(prefix.Addr(), current.prefix.Bits())]
+	}
+}
+
+// Covered calls action for every stored prefix inside the given one
+// in address order until it returns false.
+func (s *Snapshot[V]) Covered(prefix ipaddr.Prefix, action func(ipaddr.Prefix, V) bool) {
+	current := s.roots[family(prefix.Addr())]
+	for current != nil && current.prefix.Bits() < prefix.Bits() {
+		if !current.prefix.ContainsPrefix(prefix) {
+			return
+		}
+		current = current.children[bit(prefix.Addr(), current.prefix.Bits())]
+	}
+
+	if current != nil && prefix.ContainsPrefix(current.prefix) {
+		walk(current, action)
+	}
+}
+
+// ForEach calls action for every stored prefix, IPv4 first.
+func (s *Snapshot[V]) ForEach(action func(ipaddr.Prefix, V) bool) {
+	for _, root := range s.roots {
+		if root != nil && !walk(root, action) {
+			return
+		}
+	}
+}
+
+// walk uses an explicit stack, so deep IPv6 tries do not grow the goroutine stack.
+func walk[V any](root *node[V], action func(ipaddr.Prefix, V) bool) bool {
+	stack := []*node[V]{root}
+	for len(stack) != 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if current.hasValue && !action(current.prefix, current.value) {
+			return false
+		}
+		for side := 1; side >= 0; side-- {
+			if child := current.children[side]; child != nil {
+				stack = append(stack, child)
+			}
+		}
+	}
+	return true
+}
+
+func onlyChild[V any](current *node[V]) *node[V] {
+	if current.children[0] != nil {
+		return current.children[0]
+	}
+	return current.children[1]
+}
+
+func family(addr ipaddr.Addr) int {
+	if addr.Is4() {
+		return 0
+	}
+	return 1
+}
+
+// bit returns the address bit at the given position counting from the most significant one.
+func bit(addr ipaddr.Addr, position int) int {
+	high, low := addr.Uint128()
+	position += 128 - addr.BitLen()
+	if position < 64 {
+		return int(high >> (63 - position) & 1)
+	}
+	return int(low >> (127 - position) & 1)
+}
+
+func commonBits(lhs, rhs ipaddr.Prefix) int {
+	lhsHigh, lhsLow := lhs.Addr().Uint128()
+	rhsHigh, rhsLow := rhs.Addr().Uint128()
+	common := bits.LeadingZeros64(lhsHigh ^ rhsHigh)
+	if common == 64 {
+		common += bits.LeadingZeros64(lhsLow ^ rhsLow)
+	}
+	common -= 128 - lhs.Addr().BitLen()
+	return min(common, lhs.Bits(), rhs.Bits())
+}
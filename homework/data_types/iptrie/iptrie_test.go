@@ -0,0 +1,246 @@
+package iptrie
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"golang_course/homework/data_types/ipaddr"
+)
+
+// go test -v -race -bench=. .
+
+func TestLookup(t *testing.T) {
+	table := New[string]()
+	table.Insert(ipaddr.MustParsePrefix("0.0.0.0/0"), "default")
+	table.Insert(ipaddr.MustParsePrefix("10.0.0.0/8"), "private")
+	table.Insert(ipaddr.MustParsePrefix("10.1.0.0/16"), "office")
+	table.Insert(ipaddr.MustParsePrefix("10.1.2.0/24"), "lab")
+	table.Insert(ipaddr.MustParsePrefix("10.1.2.3/32"), "printer")
+	table.Insert(ipaddr.MustParsePrefix("2001:db8::/32"), "documentation")
+
+	tests := map[string]struct {
+		address string
+		prefix  string
+		value   string
+		found   bool
+	}{
+		"host route":     {address: "10.1.2.3", prefix: "10.1.2.3/32", value: "printer", found: true},
+		"subnet":         {address: "10.1.2.4", prefix: "10.1.2.0/24", value: "lab", found: true},
+		"office":         {address: "10.1.200.1", prefix: "10.1.0.0/16", value: "office", found: true},
+		"private":        {address: "10.200.0.1", prefix: "10.0.0.0/8", value: "private", found: true},
+		"default route":  {address: "8.8.8.8", prefix: "0.0.0.0/0", value: "default", found: true},
+		"ipv6 route":     {address: "2001:db8::1", prefix: "2001:db8::/32", value: "documentation", found: true},
+		"ipv6 not found": {address: "2001:db9::1", found: false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			prefix, value, found := table.Lookup(ipaddr.MustParseAddr(test.address))
+			assert.Equal(t, test.found, found)
+			assert.Equal(t, test.value, value)
+			if test.found {
+				assert.Equal(t, test.prefix, prefix.String())
+			}
+		})
+	}
+}
+
+func TestInsertDelete(t *testing.T) {
+	table := New[int]()
+	assert.False(t, table.Insert(ipaddr.MustParsePrefix("10.0.0.0/8"), 1))
+	assert.True(t, table.Insert(ipaddr.MustParsePrefix("10.0.0.0/8"), 2))
+	assert.False(t, table.Insert(ipaddr.MustParsePrefix("10.128.0.0/9"), 3))
+	assert.False(t, table.Insert(ipaddr.MustParsePrefix("10.64.0.0/10"), 4))
+	assert.Equal(t, 3, table.Len())
+
+	value, found := table.Get(ipaddr.MustParsePrefix("10.0.0.0/8"))
+	assert.True(t, found)
+	assert.Equal(t, 2, value)
+
+	// 10.0.0.0/9 exists only as a glue node between /8 and /10
+	_, found = table.Get(ipaddr.MustParsePrefix("10.0.0.0/9"))
+	assert.False(t, found)
+	assert.False(t, table.Delete(ipaddr.MustParsePrefix("10.0.0.0/9")))
+
+	assert.True(t, table.Delete(ipaddr.MustParsePrefix("10.0.0.0/8")))
+	assert.False(t, table.Delete(ipaddr.MustParsePrefix("10.0.0.0/8")))
+	assert.Equal(t, 2, table.Len())
+
+	_, _, found = table.Lookup(ipaddr.MustParseAddr("10.0.0.1"))
+	assert.False(t, found)
+	_, value, found = table.Lookup(ipaddr.MustParseAddr("10.65.0.1"))
+	assert.True(t, found)
+	assert.Equal(t, 4, value)
+
+	assert.False(t, table.Insert(ipaddr.Prefix{}, 5))
+	assert.Equal(t, 2, table.Len())
+}
+
+func TestCoveredAndCovering(t *testing.T) {
+	table := New[int]()
+	for idx, prefix := range []string{"10.0.0.0/8", "10.1.0.0/16", "10.1.2.0/24", "10.2.0.0/16", "11.0.0.0/8", "::/0"} {
+		table.Insert(ipaddr.MustParsePrefix(prefix), idx)
+	}
+
+	collect := func(iterate func(ipaddr.Prefix, func(ipaddr.Prefix, int) bool), prefix string) []string {
+		var result []string
+		iterate(ipaddr.MustParsePrefix(prefix), func(prefix ipaddr.Prefix, _ int) bool {
+			result = append(result, prefix.String())
+			return true
+		})
+		return result
+	}
+
+	snapshot := table.Snapshot()
+	assert.Equal(t, []string{"10.0.0.0/8", "10.1.0.0/16", "10.1.2.0/24", "10.2.0.0/16"}, collect(snapshot.Covered, "10.0.0.0/8"))
+	assert.Equal(t, []string{"10.1.0.0/16", "10.1.2.0/24"}, collect(snapshot.Covered, "10.1.0.0/15"))
+	assert.Empty(t, collect(snapshot.Covered, "12.0.0.0/8"))
+	assert.Equal(t, []string{"10.0.0.0/8", "10.1.0.0/16", "10.1.2.0/24"}, collect(snapshot.Covering, "10.1.2.128/25"))
+	assert.Equal(t, []string{"::/0"}, collect(snapshot.Covering, "2001:db8::/32"))
+
+	var all []string
+	snapshot.ForEach(func(prefix ipaddr.Prefix, _ int) bool {
+		all = append(all, prefix.String())
+		return len(all) < 2
+	})
+	assert.Equal(t, []string{"10.0.0.0/8", "10.1.0.0/16"}, all)
+}
+
+func TestSnapshotIsolation(t *testing.T) {
+	table := New[int]()
+	table.Insert(ipaddr.MustParsePrefix("10.0.0.0/8"), 1)
+	before := table.Snapshot()
+
+	table.Update(func(batch *Batch[int]) {
+		batch.Insert(ipaddr.MustParsePrefix("10.0.0.0/16"), 2)
+		batch.Delete(ipaddr.MustParsePrefix("10.0.0.0/8"))
+	})
+
+	_, value, found := before.Lookup(ipaddr.MustParseAddr("10.0.0.1"))
+	assert.True(t, found)
+	assert.Equal(t, 1, value)
+	assert.Equal(t, 1, before.Len())
+
+	_, value, found = table.Lookup(ipaddr.MustParseAddr("10.0.0.1"))
+	assert.True(t, found)
+	assert.Equal(t, 2, value)
+	_, _, found = table.Lookup(ipaddr.MustParseAddr("10.1.0.1"))
+	assert.False(t, found)
+}
+
+func TestRandomAgainstLinearScan(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	table := New[int]()
+	stored := map[ipaddr.Prefix]int{}
+	for idx := 0; idx < 5000; idx++ {
+		// a narrow address space makes prefixes nest and collide often
+		addr := ipaddr.AddrFromUint32(0x0A000000 | random.Uint32()&0x0000FFFF)
+		prefix, _ := ipaddr.PrefixFrom(addr, 8+random.Intn(25))
+		if random.Intn(4) == 0 {
+			_, exists := stored[prefix]
+			assert.Equal(t, exists, table.Delete(prefix))
+			delete(stored, prefix)
+		} else {
+			_, exists := stored[prefix]
+			assert.Equal(t, exists, table.Insert(prefix, idx))
+			stored[prefix] = idx
+		}
+	}
+	assert.Equal(t, len(stored), table.Len())
+
+	for range 5000 {
+		addr := ipaddr.AddrFromUint32(0x0A000000 | random.Uint32()&0x0000FFFF)
+		expectedBits, expectedValue, expectedFound := -1, 0, false
+		for prefix, value := range stored {
+			if prefix.Contains(addr) && prefix.Bits() > expectedBits {
+				expectedBits, expectedValue, expectedFound = prefix.Bits(), value, true
+			}
+		}
+
+		prefix, value, found := table.Lookup(addr)
+		assert.Equal(t, expectedFound, found)
+		assert.Equal(t, expectedValue, value)
+		if found {
+			assert.Equal(t, expectedBits, prefix.Bits())
+		}
+	}
+}
+
+func TestConcurrentReaders(t *testing.T) {
+	table := New[int]()
+	table.Insert(ipaddr.MustParsePrefix("0.0.0.0/0"), 0)
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := 0; idx < 2000; idx++ {
+				_, _, found := table.Lookup(ipaddr.AddrFromUint32(uint32(idx) << 8))
+				assert.True(t, found)
+			}
+		}()
+	}
+
+	for idx := 1; idx < 2000; idx++ {
+		prefix, _ := ipaddr.PrefixFrom(ipaddr.AddrFromUint32(uint32(idx)<<8), 24)
+		table.Insert(prefix, idx)
+	}
+	wg.Wait()
+	assert.Equal(t, 2000, table.Len())
+}
+
+func newBenchmarkTable(b *testing.B, count int) (*Table[int], []ipaddr.Addr) {
+	b.Helper()
+	random := rand.New(rand.NewSource(1))
+	table := New[int]()
+	table.Update(func(batch *Batch[int]) {
+		for idx := 0; idx < count; idx++ {
+			prefix, _ := ipaddr.PrefixFrom(ipaddr.AddrFromUint32(random.Uint32()), 16+random.Intn(17))
+			batch.Insert(prefix, idx)
+		}
+	})
+
+	addrs := make([]ipaddr.Addr, 1024)
+	for idx := range addrs {
+		addrs[idx] = ipaddr.AddrFromUint32(random.Uint32())
+	}
+	return table, addrs
+}
+
+func BenchmarkLookup1M(b *testing.B) {
+	table, addrs := newBenchmarkTable(b, 1_000_000)
+	snapshot := table.Snapshot()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _, _ = snapshot.Lookup(addrs[i%len(addrs)])
+	}
+}
+
+func BenchmarkParallelLookup1M(b *testing.B) {
+	table, addrs := newBenchmarkTable(b, 1_000_000)
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for i := 0; pb.Next(); i++ {
+			_, _, _ = table.Lookup(addrs[i%len(addrs)])
+		}
+	})
+}
+
+func BenchmarkInsert1M(b *testing.B) {
+	table, _ := newBenchmarkTable(b, 1_000_000)
+	random := rand.New(rand.NewSource(2))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		prefix, _ := ipaddr.PrefixFrom(ipaddr.AddrFromUint32(random.Uint32()), 24)
+		table.Insert(prefix, i)
+	}
+}
@@ -0,0 +1,254 @@
+package overflow
+
+import (
+	"errors"
+	"unsafe"
+)
+
+var (
+	ErrIntOverflow    = errors.New("integer overflow")
+	ErrDivisionByZero = errors.New("integer division by zero")
+)
+
+type Signed interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64
+}
+
+type Unsigned interface {
+	~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+type Integer interface {
+	Signed | Unsigned
+}
+
+func isSigned[T Integer]() bool {
+	var zero T
+	return zero-1 < zero
+}
+
+func MinValue[T Integer]() T {
+	if !isSigned[T]() {
+		return 0
+	}
+	var number T
+	return T(1) << (unsafe.Sizeof(number)*8 - 1)
+}
+
+func MaxValue[T Integer]() T {
+	return ^MinValue[T]()
+}
+
+func Add[T Integer](lhs, rhs T) (T, error) {
+	result := lhs + rhs
+	if (result < lhs) != (isSigned[T]() && rhs < 0) {
+		return 0, ErrIntOverflow
+	}
+	return result, nil
+}
+
+func Sub[T Integer](lhs, rhs T) (T, error) {
+	result := lhs - rhs
+	if (result > lhs) != (isSigned[T]() && rhs < 0) {
+		return 0, ErrIntOverflow
+	}
+	return result, nil
+}
+
+func Mul[T Integer](lhs, rhs T) (T, error) {
+	if lhs == 0 || rhs == 0 {
+		return 0, nil
+	}
+
+	minusOne := lhs - lhs - 1
+	if isSigned[T]() && ((lhs == minusOne && rhs == MinValue[T]()) || (rhs == minusOne && lhs == MinValue[T]())) {
+		return 0, ErrIntOverflow
+	}
+
+	result := lhs * rhs
+	if result/rhs != lhs {
+		return 0, ErrIntOverflow
+	}
+	return result, nil
+}
+
+func Div[T Integer](lhs, rhs T) (T, error) {
+	if rhs == 0 {
+		return 0, ErrDivisionByZero
+	}
+
+	if isSigned[T]() && lhs == MinValue[T]() && rhs == lhs-lhs-1 {
+		return 0, ErrIntOverflow
+	}
+	return lhs / rhs, nil
+}
+
+// Neg fails for the minimal signed value and for any non-zero unsigned value.
+func Neg[T Integer](number T) (T, error) {
+	if (isSigned[T]() && number == MinValue[T]()) || (!isSigned[T]() && number != 0) {
+		return 0, ErrIntOverflow
+	}
+	return -number, nil
+}
+
+func Abs[T Integer](number T) (T, error) {
+	if isSigned[T]() && number == MinValue[T]() {
+		return 0, ErrIntOverflow
+	}
+	if number < 0 {
+		return -number, nil
+	}
+	return number, nil
+}
+
+func SaturatingAdd[T Integer](lhs, rhs T) T {
+	result, err := Add(lhs, rhs)
+	if err == nil {
+		return result
+	}
+	if isSigned[T]() && rhs < 0 {
+		return MinValue[T]()
+	}
+	return MaxValue[T]()
+}
+
+func SaturatingSub[T Integer](lhs, rhs T) T {
+	result, err := Sub(lhs, rhs)
+	if err == nil {
+		return result
+	}
+	if isSigned[T]() && rhs < 0 {
+		return MaxValue[T]()
+	}
+	return MinValue[T]()
+}
+
+func SaturatingMul[T Integer](lhs, rhs T) T {
+	result, err := Mul(lhs, rhs)
+	if err == nil {
+		return result
+	}
+	if (lhs < 0) != (rhs < 0) {
+		return MinValue[T]()
+	}
+	return MaxValue[T]()
+}
+
+// SaturatingDiv panics on division by zero like the built-in operator.
+func SaturatingDiv[T Integer](lhs, rhs T) T {
+	result, err := Div(lhs, rhs)
+	if errors.Is(err, ErrIntOverflow) {
+		return MaxValue[T]()
+	}
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+func SaturatingNeg[T Integer](number T) T {
+	result, err := Neg(number)
+	if err == nil {
+		return result
+	}
+	if isSigned[T]() {
+		return MaxValue[T]()
+	}
+	return 0
+}
+
+func SaturatingAbs[T Integer](number T) T {
+	result, err := Abs(number)
+	if err != nil {
+		return MaxValue[T]()
+	}
+	return result
+}
+
+// Wrapping functions spell out the two's complement behaviour
+// of the built-in operators, so it is visible at the call site.
+func WrappingAdd[T Integer](lhs, rhs T) T {
+	return lhs + rhs
+}
+
+func WrappingSub[T Integer](lhs, rhs T) T {
+	return lhs - rhs
+}
+
+func WrappingMul[T Integer](lhs, rhs T) T {
+	return lhs * rhs
+}
+
+func WrappingDiv[T Integer](lhs, rhs T) T {
+	return lhs / rhs
+}
+
+func WrappingNeg[T Integer](number T) T {
+	return -number
+}
+
+func WrappingAbs[T Integer](number T) T {
+	if number < 0 {
+		return -number
+	}
+	return number
+}
+
+// Checked carries the first error through a chain of operations:
+//
+//	value, err := overflow.Check(price).Mul(count).Add(fee).Value()
+type Checked[T Integer] struct {
+	value T
+	err   error
+}
+
+func Check[T Integer](number T) Checked[T] {
+	return Checked[T]{value: number}
+}
+
+func (c Checked[T]) Add(number T) Checked[T] {
+	return c.apply(func() (T, error) { return Add(c.value, number) })
+}
+
+func (c Checked[T]) Sub(number T) Checked[T] {
+	return c.apply(func() (T, error) { return Sub(c.value, number) })
+}
+
+func (c Checked[T]) Mul(number T) Checked[T] {
+	return c.apply(func() (T, error) { return Mul(c.value, number) })
+}
+
+func (c Checked[T]) Div(number T) Checked[T] {
+	return c.apply(func() (T, error) { return Div(c.value, number) })
+}
+
+func (c Checked[T]) Neg() Checked[T] {
+	return c.apply(func() (T, error) { return Neg(c.value) })
+}
+
+func (c Checked[T]) Abs() Checked[T] {
+	return c.apply(func() (T, error) { return Abs(c.value) })
+}
+
+func (c Checked[T]) Value() (T, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	return c.value, nil
+}
+
+func (c Checked[T]) Overflowed() bool {
+	return errors.Is(c.err, ErrIntOverflow)
+}
+
+func (c Checked[T]) Err() error {
+	return c.err
+}
+
+func (c Checked[T]) apply(operation func() (T, error)) Checked[T] {
+	if c.err != nil {
+		return c
+	}
+	value, err := operation()
+	return Checked[T]{value: value, err: err}
+}
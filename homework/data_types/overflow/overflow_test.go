@@ -0,0 +1,206 @@
+package overflow
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// go test -v -fuzz=FuzzInt64 .
+
+func TestBounds(t *testing.T) {
+	assert.Equal(t, int8(math.MinInt8), MinValue[int8]())
+	assert.Equal(t, int8(math.MaxInt8), MaxValue[int8]())
+	assert.Equal(t, int64(math.MinInt64), MinValue[int64]())
+	assert.Equal(t, uint16(0), MinValue[uint16]())
+	assert.Equal(t, uint16(math.MaxUint16), MaxValue[uint16]())
+	assert.Equal(t, uint64(math.MaxUint64), MaxValue[uint64]())
+}
+
+// checkExhaustive compares every pair of 8-bit operands with the exact result
+// computed on int, which cannot overflow for such small numbers.
+func checkExhaustive[T int8 | uint8](t *testing.T) {
+	low, high := int(MinValue[T]()), int(MaxValue[T]())
+	clamp := func(exact int) T {
+		return T(max(low, min(high, exact)))
+	}
+	fits := func(exact int) bool {
+		return exact >= low && exact <= high
+	}
+	check := func(name string, exact int, result T, err error) {
+		if fits(exact) {
+			if err != nil || int(result) != exact {
+				t.Fatalf("%s: expected %d, got %d (%v)", name, exact, result, err)
+			}
+		} else if err != ErrIntOverflow || result != 0 {
+			t.Fatalf("%s: expected overflow for %d, got %d (%v)", name, exact, result, err)
+		}
+	}
+
+	for lhsNumber := low; lhsNumber <= high; lhsNumber++ {
+		lhs := T(lhsNumber)
+
+		result, err := Neg(lhs)
+		check("neg", -lhsNumber, result, err)
+		result, err = Abs(lhs)
+		check("abs", max(lhsNumber, -lhsNumber), result, err)
+		if SaturatingNeg(lhs) != clamp(-lhsNumber) || SaturatingAbs(lhs) != clamp(max(lhsNumber, -lhsNumber)) {
+			t.Fatalf("saturating neg/abs of %d", lhsNumber)
+		}
+		if WrappingNeg(lhs) != T(-lhsNumber) || WrappingAbs(lhs) != T(max(lhsNumber, -lhsNumber)) {
+			t.Fatalf("wrapping neg/abs of %d", lhsNumber)
+		}
+
+		for rhsNumber := low; rhsNumber <= high; rhsNumber++ {
+			rhs := T(rhsNumber)
+
+			result, err = Add(lhs, rhs)
+			check("add", lhsNumber+rhsNumber, result, err)
+			result, err = Sub(lhs, rhs)
+			check("sub", lhsNumber-rhsNumber, result, err)
+			result, err = Mul(lhs, rhs)
+			check("mul", lhsNumber*rhsNumber, result, err)
+
+			if SaturatingAdd(lhs, rhs) != clamp(lhsNumber+rhsNumber) ||
+				SaturatingSub(lhs, rhs) != clamp(lhsNumber-rhsNumber) ||
+				SaturatingMul(lhs, rhs) != clamp(lhsNumber*rhsNumber) {
+				t.Fatalf("saturating operation on %d and %d", lhsNumber, rhsNumber)
+			}
+
+			if WrappingAdd(lhs, rhs) != T(lhsNumber+rhsNumber) ||
+				WrappingSub(lhs, rhs) != T(lhsNumber-rhsNumber) ||
+				WrappingMul(lhs, rhs) != T(lhsNumber*rhsNumber) {
+				t.Fatalf("wrapping operation on %d and %d", lhsNumber, rhsNumber)
+			}
+
+			if rhsNumber == 0 {
+				_, err = Div(lhs, rhs)
+				if err != ErrDivisionByZero {
+					t.Fatalf("div by zero: %v", err)
+				}
+				continue
+			}
+
+			result, err = Div(lhs, rhs)
+			check("div", lhsNumber/rhsNumber, result, err)
+			if SaturatingDiv(lhs, rhs) != clamp(lhsNumber/rhsNumber) || WrappingDiv(lhs, rhs) != T(lhsNumber/rhsNumber) {
+				t.Fatalf("div of %d and %d", lhsNumber, rhsNumber)
+			}
+		}
+	}
+}
+
+func TestExhaustiveInt8(t *testing.T) {
+	checkExhaustive[int8](t)
+}
+
+func TestExhaustiveUint8(t *testing.T) {
+	checkExhaustive[uint8](t)
+}
+
+func TestSaturatingDivByZero(t *testing.T) {
+	assert.PanicsWithValue(t, ErrDivisionByZero, func() { SaturatingDiv(1, 0) })
+}
+
+func TestChecked(t *testing.T) {
+	tests := map[string]struct {
+		checked    Checked[int32]
+		result     int32
+		err        error
+		overflowed bool
+	}{
+		"no overflow": {
+			checked: Check[int32](1000).Mul(1000).Add(7).Sub(8).Div(3),
+			result:  333333,
+		},
+		"overflow in the middle": {
+			checked:    Check[int32](math.MaxInt32).Add(1).Sub(10),
+			err:        ErrIntOverflow,
+			overflowed: true,
+		},
+		"sticky overflow": {
+			checked:    Check[int32](math.MinInt32).Abs().Neg().Div(-1),
+			err:        ErrIntOverflow,
+			overflowed: true,
+		},
+		"division by zero": {
+			checked: Check[int32](10).Div(0).Add(1),
+			err:     ErrDivisionByZero,
+		},
+		"negation": {
+			checked: Check[int32](-5).Abs().Neg(),
+			result:  -5,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := test.checked.Value()
+			assert.Equal(t, test.result, result)
+			assert.ErrorIs(t, err, test.err)
+			assert.Equal(t, test.overflowed, test.checked.Overflowed())
+		})
+	}
+
+	_, err := Check[uint8](200).Add(100).Value()
+	require.ErrorIs(t, err, ErrIntOverflow)
+}
+
+func bigResult(operation string, lhs, rhs *big.Int) *big.Int {
+	switch operation {
+	case "add":
+		return new(big.Int).Add(lhs, rhs)
+	case "sub":
+		return new(big.Int).Sub(lhs, rhs)
+	case "mul":
+		return new(big.Int).Mul(lhs, rhs)
+	}
+	return new(big.Int).Quo(lhs, rhs)
+}
+
+func checkAgainstBig[T Integer](t *testing.T, lhs, rhs T, toBig func(T) *big.Int) {
+	low, high := toBig(MinValue[T]()), toBig(MaxValue[T]())
+	operations := map[string]func(T, T) (T, error){"add": Add[T], "sub": Sub[T], "mul": Mul[T], "div": Div[T]}
+	for name, operation := range operations {
+		result, err := operation(lhs, rhs)
+		if name == "div" && rhs == 0 {
+			assert.ErrorIs(t, err, ErrDivisionByZero)
+			continue
+		}
+
+		exact := bigResult(name, toBig(lhs), toBig(rhs))
+		if exact.Cmp(low) < 0 || exact.Cmp(high) > 0 {
+			assert.ErrorIs(t, err, ErrIntOverflow, "%s(%d, %d)", name, lhs, rhs)
+		} else {
+			assert.NoError(t, err, "%s(%d, %d)", name, lhs, rhs)
+			assert.Zero(t, exact.Cmp(toBig(result)), "%s(%d, %d) = %d", name, lhs, rhs, result)
+		}
+	}
+}
+
+func FuzzInt64(f *testing.F) {
+	f.Add(int64(math.MaxInt64), int64(1))
+	f.Add(int64(math.MinInt64), int64(-1))
+	f.Add(int64(math.MaxInt32), int64(math.MaxInt32))
+	f.Fuzz(func(t *testing.T, lhs, rhs int64) {
+		checkAgainstBig(t, lhs, rhs, big.NewInt)
+	})
+}
+
+func FuzzUint64(f *testing.F) {
+	f.Add(uint64(math.MaxUint64), uint64(1))
+	f.Add(uint64(math.MaxUint32), uint64(math.MaxUint32+2))
+	f.Fuzz(func(t *testing.T, lhs, rhs uint64) {
+		checkAgainstBig(t, lhs, rhs, func(number uint64) *big.Int { return new(big.Int).SetUint64(number) })
+	})
+}
+
+func FuzzInt32(f *testing.F) {
+	f.Add(int32(math.MinInt32), int32(-1))
+	f.Fuzz(func(t *testing.T, lhs, rhs int32) {
+		checkAgainstBig(t, lhs, rhs, func(number int32) *big.Int { return big.NewInt(int64(number)) })
+	})
+}
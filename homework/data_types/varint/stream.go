@@ -0,0 +1,121 @@
+package varint
+
+import (
+	"bufio"
+	"errors"
+	"io"
+)
+
+type Encoder struct {
+	writer io.Writer
+	buffer []byte
+}
+
+func NewEncoder(writer io.Writer) *Encoder {
+	return &Encoder{writer: writer, buffer: make([]byte, 0, MaxLen)}
+}
+
+func (e *Encoder) WriteUvarint(value uint64) error {
+	return e.flush(AppendUvarint(e.buffer[:0], value))
+}
+
+func (e *Encoder) WriteVarint(value int64) error {
+	return e.flush(AppendVarint(e.buffer[:0], value))
+}
+
+func (e *Encoder) WritePacked(values []int64) error {
+	return e.flush(AppendPacked(e.buffer[:0], values))
+}
+
+func (e *Encoder) flush(data []byte) error {
+	_, err := e.writer.Write(data)
+	e.buffer = data[:0]
+	return err
+}
+
+// Decoder reads byte by byte, readers without ReadByte are wrapped into bufio.Reader,
+// so the decoder may read ahead of the last decoded value.
+type Decoder struct {
+	reader io.ByteReader
+}
+
+func NewDecoder(reader io.Reader) *Decoder {
+	byteReader, ok := reader.(io.ByteReader)
+	if !ok {
+		byteReader = bufio.NewReader(reader)
+	}
+	return &Decoder{reader: byteReader}
+}
+
+// ReadUvarint returns io.EOF only when the stream ends before the first byte.
+func (d *Decoder) ReadUvarint() (uint64, error) {
+	var value uint64
+	for idx := 0; idx < MaxLen; idx++ {
+		current, err := d.reader.ReadByte()
+		if err != nil {
+			if idx > 0 && errors.Is(err, io.EOF) {
+				return 0, ErrTruncated
+			}
+			return 0, err
+		}
+
+		if idx == MaxLen-1 && current > 1 {
+			return 0, ErrOverflow
+		}
+
+		value |= uint64(current&0x7F) << (7 * idx)
+		if current < 0x80 {
+			return value, nil
+		}
+	}
+	return 0, ErrOverflow
+}
+
+func (d *Decoder) ReadVarint() (int64, error) {
+	value, err := d.ReadUvarint()
+	return UnZigZag(value), err
+}
+
+func (d *Decoder) ReadPacked() ([]int64, error) {
+	count, err := d.ReadUvarint()
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return []int64{}, nil
+	}
+	if count > MaxPackedCount {
+		return nil, ErrCorrupted
+	}
+
+	reference, err := d.ReadVarint()
+	if err != nil {
+		return nil, truncated(err)
+	}
+
+	width, err := d.reader.ReadByte()
+	if err != nil {
+		return nil, truncated(err)
+	}
+	if width > 64 {
+		return nil, ErrCorrupted
+	}
+
+	packed := make([]byte, packedLen(int(count), int(width)))
+	for idx := range packed {
+		if packed[idx], err = d.reader.ReadByte(); err != nil {
+			return nil, truncated(err)
+		}
+	}
+
+	values := make([]int64, count)
+	unpackBits(packed, values, reference, int(width))
+	return values, nil
+}
+
+func truncated(err error) error {
+	if errors.Is(err, io.EOF) {
+		return ErrTruncated
+	}
+	return err
+}
@@ -0,0 +1,171 @@
+package varint
+
+import (
+	"errors"
+	"math/bits"
+)
+
+var (
+	ErrTruncated = errors.New("varint: truncated input")
+	ErrOverflow  = errors.New("varint: value overflows 64 bits")
+	ErrCorrupted = errors.New("varint: corrupted packed block")
+)
+
+// MaxLen is the longest LEB128 encoding of a 64-bit value,
+// MaxPackedCount limits the allocation made for a single packed block.
+const (
+	MaxLen         = 10
+	MaxPackedCount = 1 << 26
+)
+
+// ZigZag maps signed values to unsigned so that small magnitudes stay small:
+// 0 -> 0, -1 -> 1, 1 -> 2, -2 -> 3 ...
+func ZigZag(value int64) uint64 {
+	return uint64(value<<1) ^ uint64(value>>63)
+}
+
+func UnZigZag(value uint64) int64 {
+	return int64(value>>1) ^ -int64(value&1)
+}
+
+// AppendUvarint appends the unsigned LEB128 encoding: 7 bits per byte,
+// the high bit tells that more bytes follow.
+func AppendUvarint(dst []byte, value uint64) []byte {
+	for value >= 0x80 {
+		dst = append(dst, byte(value)|0x80)
+		value >>= 7
+	}
+	return append(dst, byte(value))
+}
+
+func AppendVarint(dst []byte, value int64) []byte {
+	return AppendUvarint(dst, ZigZag(value))
+}
+
+// Uvarint decodes a value from the beginning of src and returns it with the number of bytes read.
+func Uvarint(src []byte) (uint64, int, error) {
+	var value uint64
+	for idx := 0; idx < len(src); idx++ {
+		if idx == MaxLen {
+			return 0, 0, ErrOverflow
+		}
+
+		current := src[idx]
+		if idx == MaxLen-1 && current > 1 {
+			return 0, 0, ErrOverflow
+		}
+
+		value |= uint64(current&0x7F) << (7 * idx)
+		if current < 0x80 {
+			return value, idx + 1, nil
+		}
+	}
+	return 0, 0, ErrTruncated
+}
+
+func Varint(src []byte) (int64, int, error) {
+	value, size, err := Uvarint(src)
+	return UnZigZag(value), size, err
+}
+
+func UvarintLen(value uint64) int {
+	return max(1, (bits.Len64(value)+6)/7)
+}
+
+// AppendPacked stores values with frame-of-reference and bit-packing:
+// count, the minimal value as the reference, the bit width of the largest
+// delta from the reference and then all deltas packed back to back.
+func AppendPacked(dst []byte, values []int64) []byte {
+	dst = AppendUvarint(dst, uint64(len(values)))
+	if len(values) == 0 {
+		return dst
+	}
+
+	reference, maxDelta := frame(values)
+	width := bits.Len64(maxDelta)
+	dst = AppendVarint(dst, reference)
+	dst = append(dst, byte(width))
+
+	offset := len(dst)
+	dst = append(dst, make([]byte, packedLen(len(values), width))...)
+	packBits(dst[offset:], values, reference, width)
+	return dst
+}
+
+func Packed(src []byte) ([]int64, int, error) {
+	count, read, err := Uvarint(src)
+	if err != nil {
+		return nil, 0, err
+	}
+	if count == 0 {
+		return []int64{}, read, nil
+	}
+	if count > MaxPackedCount {
+		return nil, 0, ErrCorrupted
+	}
+
+	reference, size, err := Varint(src[read:])
+	if err != nil {
+		return nil, 0, err
+	}
+	read += size
+
+	if read >= len(src) {
+		return nil, 0, ErrTruncated
+	}
+	width := int(src[read])
+	read++
+	if width > 64 {
+		return nil, 0, ErrCorrupted
+	}
+
+	size = packedLen(int(count), width)
+	if len(src)-read < size {
+		return nil, 0, ErrTruncated
+	}
+
+	values := make([]int64, count)
+	unpackBits(src[read:read+size], values, reference, width)
+	return values, read + size, nil
+}
+
+func frame(values []int64) (int64, uint64) {
+	minimum, maximum := values[0], values[0]
+	for _, value := range values[1:] {
+		minimum, maximum = min(minimum, value), max(maximum, value)
+	}
+	return minimum, uint64(maximum) - uint64(minimum)
+}
+
+func packedLen(count, width int) int {
+	return (count*width + 7) / 8
+}
+
+func packBits(dst []byte, values []int64, reference int64, width int) {
+	offset := 0
+	for _, value := range values {
+		delta := uint64(value) - uint64(reference)
+		for written := 0; written < width; {
+			shift := offset % 8
+			take := min(width-written, 8-shift)
+			dst[offset/8] |= byte(delta>>written&(1<<take-1)) << shift
+			written += take
+			offset += take
+		}
+	}
+}
+
+func unpackBits(src []byte, values []int64, reference int64, width int) {
+	offset := 0
+	for idx := range values {
+		var delta uint64
+		for read := 0; read < width; {
+			shift := offset % 8
+			take := min(width-read, 8-shift)
+			delta |= uint64(src[offset/8]>>shift&(1<<take-1)) << read
+			read += take
+			offset += take
+		}
+		values[idx] = int64(uint64(reference) + delta)
+	}
+}
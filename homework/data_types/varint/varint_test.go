@@ -0,0 +1,202 @@
+package varint
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// go test -v -fuzz=FuzzPacked .
+
+func TestZigZag(t *testing.T) {
+	tests := map[int64]uint64{
+		0:             0,
+		-1:            1,
+		1:             2,
+		-2:            3,
+		2:             4,
+		math.MaxInt64: math.MaxUint64 - 1,
+		math.MinInt64: math.MaxUint64,
+	}
+
+	for value, encoded := range tests {
+		assert.Equal(t, encoded, ZigZag(value))
+		assert.Equal(t, value, UnZigZag(encoded))
+	}
+}
+
+func TestUvarint(t *testing.T) {
+	tests := map[string]struct {
+		value   uint64
+		encoded []byte
+	}{
+		"zero":        {value: 0, encoded: []byte{0x00}},
+		"one byte":    {value: 127, encoded: []byte{0x7F}},
+		"two bytes":   {value: 300, encoded: []byte{0xAC, 0x02}},
+		"wikipedia":   {value: 624485, encoded: []byte{0xE5, 0x8E, 0x26}},
+		"max uint64":  {value: math.MaxUint64, encoded: []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x01}},
+		"power of 63": {value: 1 << 63, encoded: []byte{0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x01}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			encoded := AppendUvarint(nil, test.value)
+			assert.Equal(t, test.encoded, encoded)
+			assert.Equal(t, len(encoded), UvarintLen(test.value))
+
+			value, size, err := Uvarint(append(encoded, 0xFF))
+			require.NoError(t, err)
+			assert.Equal(t, test.value, value)
+			assert.Equal(t, len(test.encoded), size)
+		})
+	}
+}
+
+func TestUvarintErrors(t *testing.T) {
+	_, _, err := Uvarint(nil)
+	assert.ErrorIs(t, err, ErrTruncated)
+	_, _, err = Uvarint([]byte{0x80, 0x80})
+	assert.ErrorIs(t, err, ErrTruncated)
+	_, _, err = Uvarint([]byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x02})
+	assert.ErrorIs(t, err, ErrOverflow)
+	_, _, err = Uvarint(bytes.Repeat([]byte{0x80}, 11))
+	assert.ErrorIs(t, err, ErrOverflow)
+}
+
+func TestPacked(t *testing.T) {
+	tests := map[string]struct {
+		values []int64
+		size   int
+	}{
+		"empty":          {values: []int64{}, size: 1},
+		"same values":    {values: []int64{7, 7, 7, 7}, size: 3},
+		"small deltas":   {values: []int64{1000, 1001, 1003, 1007, 1000, 1005, 1002, 1001}, size: 7},
+		"negative":       {values: []int64{-5, -1, -3, 0}, size: 5},
+		"full range":     {values: []int64{math.MinInt64, math.MaxInt64, 0}, size: 1 + 10 + 1 + 24},
+		"single element": {values: []int64{-42}, size: 3},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			encoded := AppendPacked(nil, test.values)
+			assert.Len(t, encoded, test.size)
+
+			values, size, err := Packed(append(encoded, 0xAA))
+			require.NoError(t, err)
+			assert.Equal(t, test.values, values)
+			assert.Equal(t, len(encoded), size)
+		})
+	}
+
+	encoded := AppendPacked(nil, []int64{1, 2, 3, 100})
+	_, _, err := Packed(encoded[:len(encoded)-1])
+	assert.ErrorIs(t, err, ErrTruncated)
+	_, _, err = Packed([]byte{0x01, 0x00, 65})
+	assert.ErrorIs(t, err, ErrCorrupted)
+	_, _, err = Packed(AppendUvarint(nil, math.MaxUint64))
+	assert.ErrorIs(t, err, ErrCorrupted)
+}
+
+func TestStream(t *testing.T) {
+	var buffer bytes.Buffer
+	encoder := NewEncoder(&buffer)
+	require.NoError(t, encoder.WriteUvarint(300))
+	require.NoError(t, encoder.WriteVarint(-300))
+	require.NoError(t, encoder.WritePacked([]int64{10, 12, 11}))
+	require.NoError(t, encoder.WritePacked(nil))
+	require.NoError(t, encoder.WriteUvarint(math.MaxUint64))
+
+	// io.MultiReader has no ReadByte, so the decoder has to buffer it
+	decoder := NewDecoder(io.MultiReader(&buffer))
+	unsigned, err := decoder.ReadUvarint()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(300), unsigned)
+
+	signed, err := decoder.ReadVarint()
+	require.NoError(t, err)
+	assert.Equal(t, int64(-300), signed)
+
+	values, err := decoder.ReadPacked()
+	require.NoError(t, err)
+	assert.Equal(t, []int64{10, 12, 11}, values)
+
+	values, err = decoder.ReadPacked()
+	require.NoError(t, err)
+	assert.Empty(t, values)
+
+	unsigned, err = decoder.ReadUvarint()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(math.MaxUint64), unsigned)
+
+	_, err = decoder.ReadUvarint()
+	assert.ErrorIs(t, err, io.EOF)
+
+	_, err = NewDecoder(bytes.NewReader([]byte{0x80})).ReadUvarint()
+	assert.ErrorIs(t, err, ErrTruncated)
+	_, err = NewDecoder(bytes.NewReader([]byte{0x03, 0x02, 0x08, 0xFF})).ReadPacked()
+	assert.ErrorIs(t, err, ErrTruncated)
+}
+
+func FuzzUvarint(f *testing.F) {
+	f.Add(uint64(0), int64(0))
+	f.Add(uint64(math.MaxUint64), int64(math.MinInt64))
+	f.Fuzz(func(t *testing.T, unsigned uint64, signed int64) {
+		encoded := AppendUvarint(nil, unsigned)
+		assert.Equal(t, binary.AppendUvarint(nil, unsigned), encoded)
+		value, size, err := Uvarint(encoded)
+		require.NoError(t, err)
+		assert.Equal(t, unsigned, value)
+		assert.Equal(t, len(encoded), size)
+
+		encoded = AppendVarint(nil, signed)
+		assert.Equal(t, binary.AppendVarint(nil, signed), encoded)
+		decoded, _, err := Varint(encoded)
+		require.NoError(t, err)
+		assert.Equal(t, signed, decoded)
+	})
+}
+
+func FuzzUvarintDecode(f *testing.F) {
+	f.Add([]byte{0xAC, 0x02})
+	f.Add([]byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x02})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		expected, expectedSize := binary.Uvarint(data)
+		value, size, err := Uvarint(data)
+		if expectedSize <= 0 {
+			assert.Error(t, err)
+			return
+		}
+		require.NoError(t, err)
+		assert.Equal(t, expected, value)
+		assert.Equal(t, expectedSize, size)
+
+		streamed, err := NewDecoder(bytes.NewReader(data)).ReadUvarint()
+		require.NoError(t, err)
+		assert.Equal(t, expected, streamed)
+	})
+}
+
+func FuzzPacked(f *testing.F) {
+	f.Add([]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		values := make([]int64, len(data)/8)
+		for idx := range values {
+			values[idx] = int64(binary.LittleEndian.Uint64(data[idx*8:]))
+		}
+
+		encoded := AppendPacked(nil, values)
+		decoded, size, err := Packed(encoded)
+		require.NoError(t, err)
+		assert.Equal(t, values, decoded)
+		assert.Equal(t, len(encoded), size)
+
+		// arbitrary input must never panic
+		_, _, _ = Packed(data)
+		_, _ = NewDecoder(bytes.NewReader(data)).ReadPacked()
+	})
+}
@@ -0,0 +1,246 @@
+package errcode
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+)
+
+type Category int
+
+const (
+	CategoryInternal Category = iota
+	CategoryInvalidInput
+	CategoryNotFound
+	CategoryConflict
+	CategoryUnavailable
+)
+
+func (c Category) String() string {
+	switch c {
+	case CategoryInvalidInput:
+		return "invalid input"
+	case CategoryNotFound:
+		return "not found"
+	case CategoryConflict:
+		return "conflict"
+	case CategoryUnavailable:
+		return "unavailable"
+	}
+	return "internal"
+}
+
+// Exit codes follow sysexits.h, so shell scripts can tell failures apart.
+var categoryStatuses = map[Category]struct{ http, exit int }{
+	CategoryInternal:     {http: http.StatusInternalServerError, exit: 70},
+	CategoryInvalidInput: {http: http.StatusBadRequest, exit: 65},
+	CategoryNotFound:     {http: http.StatusNotFound, exit: 66},
+	CategoryConflict:     {http: http.StatusConflict, exit: 73},
+	CategoryUnavailable:  {http: http.StatusServiceUnavailable, exit: 69},
+}
+
+var ErrDuplicateCode = errors.New("error code is already registered")
+
+// Definition describes a code, zero HTTPStatus and ExitCode are taken from the category.
+type Definition struct {
+	Name       string
+	Message    string
+	Category   Category
+	Retryable  bool
+	HTTPStatus int
+	ExitCode   int
+}
+
+// Code is a registered error code. It is an error itself, so it can be
+// returned and matched with errors.Is like a sentinel.
+type Code struct {
+	definition Definition
+}
+
+var (
+	registryMutex sync.RWMutex
+	registry      = make(map[string]*Code)
+)
+
+// Unknown is reported for errors that carry no code.
+var Unknown = MustRegister(Definition{Name: "unknown", Message: "unknown error", Category: CategoryInternal})
+
+func Register(definition Definition) (*Code, error) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	if _, found := registry[definition.Name]; found {
+		return nil, fmt.Errorf("%w: %q", ErrDuplicateCode, definition.Name)
+	}
+
+	status := categoryStatuses[definition.Category]
+	if definition.HTTPStatus == 0 {
+		definition.HTTPStatus = status.http
+	}
+	if definition.ExitCode == 0 {
+		definition.ExitCode = status.exit
+	}
+
+	code := &Code{definition: definition}
+	registry[definition.Name] = code
+	return code, nil
+}
+
+// MustRegister is meant for package level variables and panics on duplicates.
+func MustRegister(definition Definition) *Code {
+	code, err := Register(definition)
+	if err != nil {
+		panic(err)
+	}
+	return code
+}
+
+func Lookup(name string) (*Code, bool) {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	code, found := registry[name]
+	return code, found
+}
+
+// Codes returns all registered codes ordered by name.
+func Codes() []*Code {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	codes := make([]*Code, 0, len(registry))
+	for _, code := range registry {
+		codes = append(codes, code)
+	}
+	slices.SortFunc(codes, func(lhs, rhs *Code) int {
+		return strings.Compare(lhs.Name(), rhs.Name())
+	})
+	return codes
+}
+
+func (c *Code) Name() string {
+	return c.definition.Name
+}
+
+func (c *Code) Message() string {
+	return c.definition.Message
+}
+
+func (c *Code) Category() Category {
+	return c.definition.Category
+}
+
+func (c *Code) Retryable() bool {
+	return c.definition.Retryable
+}
+
+func (c *Code) HTTPStatus() int {
+	return c.definition.HTTPStatus
+}
+
+func (c *Code) ExitCode() int {
+	return c.definition.ExitCode
+}
+
+func (c *Code) Error() string {
+	return c.definition.Message
+}
+
+// New creates an error with the code message.
+func (c *Code) New() error {
+	return &Error{code: c}
+}
+
+// Errorf formats the message like fmt.Errorf, errors wrapped with %w stay
+// reachable with errors.Is and errors.As.
+func (c *Code) Errorf(format string, args ...any) error {
+	err := fmt.Errorf(format, args...)
+	cause := errors.Unwrap(err)
+	// with several %w the formatted error keeps the causes in Unwrap() []error
+	if _, ok := err.(interface{ Unwrap() []error }); ok {
+		cause = err
+	}
+	return &Error{code: c, message: err.Error(), cause: cause}
+}
+
+// Wrap attaches the code to err, nil stays nil.
+func (c *Code) Wrap(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{code: c, cause: err}
+}
+
+type Error struct {
+	code    *Code
+	message string
+	cause   error
+}
+
+func (e *Error) Code() *Code {
+	return e.code
+}
+
+func (e *Error) Error() string {
+	switch {
+	case e.message != "":
+		return e.message
+	case e.cause != nil:
+		return e.code.Message() + ": " + e.cause.Error()
+	}
+	return e.code.Message()
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// Is matches errors by code, not by message.
+func (e *Error) Is(target error) bool {
+	switch target := target.(type) {
+	case *Code:
+		return e.code == target
+	case *Error:
+		return e.code == target.code
+	}
+	return false
+}
+
+// CodeOf returns the outermost code in the chain, nil for nil and Unknown when there is none.
+func CodeOf(err error) *Code {
+	if err == nil {
+		return nil
+	}
+
+	var codeErr *Error
+	if errors.As(err, &codeErr) {
+		return codeErr.code
+	}
+
+	var code *Code
+	if errors.As(err, &code) {
+		return code
+	}
+	return Unknown
+}
+
+func HTTPStatus(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	return CodeOf(err).HTTPStatus()
+}
+
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	return CodeOf(err).ExitCode()
+}
+
+func IsRetryable(err error) bool {
+	return err != nil && CodeOf(err).Retryable()
+}
@@ -0,0 +1,142 @@
+package errcode
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// go test -v .
+
+var (
+	ZeroNumberErr = MustRegister(Definition{
+		Name:     "math.zero_number",
+		Message:  "division by zero",
+		Category: CategoryInvalidInput,
+	})
+	EvenNumberErr = MustRegister(Definition{
+		Name:     "math.even_number",
+		Message:  "even numbers are not supported",
+		Category: CategoryInvalidInput,
+		ExitCode: 2,
+	})
+	StorageErr = MustRegister(Definition{
+		Name:      "storage.unavailable",
+		Message:   "storage is unavailable",
+		Category:  CategoryUnavailable,
+		Retryable: true,
+	})
+	UserNotFoundErr = MustRegister(Definition{
+		Name:       "user.not_found",
+		Message:    "user not found",
+		Category:   CategoryNotFound,
+		HTTPStatus: http.StatusGone,
+	})
+)
+
+func divide(lhs, rhs int) (int, error) {
+	if rhs == 0 {
+		return 0, ZeroNumberErr.New()
+	} else if lhs%2 == 0 || rhs%2 == 0 {
+		return 0, EvenNumberErr.Errorf("cannot divide %d by %d", lhs, rhs)
+	}
+
+	return lhs / rhs, nil
+}
+
+func TestMatchingByCode(t *testing.T) {
+	_, err := divide(100, 0)
+	assert.EqualError(t, err, "division by zero")
+	assert.ErrorIs(t, err, ZeroNumberErr)
+	assert.NotErrorIs(t, err, EvenNumberErr)
+
+	_, err = divide(100, 3)
+	assert.EqualError(t, err, "cannot divide 100 by 3")
+	assert.ErrorIs(t, err, EvenNumberErr)
+	assert.ErrorIs(t, fmt.Errorf("handler: %w", err), EvenNumberErr.New())
+
+	wrapped := StorageErr.Wrap(io.ErrUnexpectedEOF)
+	assert.EqualError(t, wrapped, "storage is unavailable: unexpected EOF")
+	assert.ErrorIs(t, wrapped, StorageErr)
+	assert.ErrorIs(t, wrapped, io.ErrUnexpectedEOF)
+	assert.Nil(t, StorageErr.Wrap(nil))
+
+	causeErr := StorageErr.Errorf("read block %d: %w", 7, io.EOF)
+	assert.ErrorIs(t, causeErr, io.EOF)
+
+	causesErr := StorageErr.Errorf("read block %d: %w, close: %w", 7, io.EOF, io.ErrClosedPipe)
+	assert.EqualError(t, causesErr, "read block 7: EOF, close: io: read/write on closed pipe")
+	assert.ErrorIs(t, causesErr, StorageErr)
+	assert.ErrorIs(t, causesErr, io.EOF)
+	assert.ErrorIs(t, causesErr, io.ErrClosedPipe)
+}
+
+func TestStatuses(t *testing.T) {
+	tests := map[string]struct {
+		err       error
+		code      *Code
+		http      int
+		exit      int
+		retryable bool
+	}{
+		"no error": {
+			err: nil, code: nil, http: http.StatusOK, exit: 0,
+		},
+		"invalid input": {
+			err: ZeroNumberErr.New(), code: ZeroNumberErr, http: http.StatusBadRequest, exit: 65,
+		},
+		"exit code override": {
+			err: EvenNumberErr.New(), code: EvenNumberErr, http: http.StatusBadRequest, exit: 2,
+		},
+		"http status override": {
+			err: fmt.Errorf("lookup: %w", UserNotFoundErr.New()), code: UserNotFoundErr, http: http.StatusGone, exit: 66,
+		},
+		"retryable": {
+			err: StorageErr.Wrap(io.EOF), code: StorageErr, http: http.StatusServiceUnavailable, exit: 69, retryable: true,
+		},
+		"bare code as sentinel": {
+			err: fmt.Errorf("wrapped: %w", StorageErr), code: StorageErr, http: http.StatusServiceUnavailable, exit: 69, retryable: true,
+		},
+		"plain error": {
+			err: io.EOF, code: Unknown, http: http.StatusInternalServerError, exit: 70,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.code, CodeOf(test.err))
+			assert.Equal(t, test.http, HTTPStatus(test.err))
+			assert.Equal(t, test.exit, ExitCode(test.err))
+			assert.Equal(t, test.retryable, IsRetryable(test.err))
+		})
+	}
+}
+
+func TestOutermostCodeWins(t *testing.T) {
+	err := StorageErr.Wrap(UserNotFoundErr.New())
+	assert.Equal(t, StorageErr, CodeOf(err))
+	assert.ErrorIs(t, err, UserNotFoundErr)
+}
+
+func TestRegistry(t *testing.T) {
+	code, found := Lookup("storage.unavailable")
+	require.True(t, found)
+	assert.Equal(t, StorageErr, code)
+	assert.Equal(t, CategoryUnavailable, code.Category())
+	assert.Equal(t, "unavailable", code.Category().String())
+
+	_, err := Register(Definition{Name: "storage.unavailable"})
+	assert.True(t, errors.Is(err, ErrDuplicateCode))
+	assert.Panics(t, func() { MustRegister(Definition{Name: "math.zero_number"}) })
+
+	var names []string
+	for _, code := range Codes() {
+		names = append(names, code.Name())
+	}
+	assert.Equal(t, []string{"math.even_number", "math.zero_number", "storage.unavailable", "unknown", "user.not_found"}, names)
+}
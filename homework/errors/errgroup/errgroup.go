@@ -0,0 +1,124 @@
+package errgroup
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"golang_course/homework/errors/multierror"
+)
+
+// TaskError tells which task failed, Index is the order of the Go call.
+type TaskError struct {
+	Task  string
+	Index int
+	Err   error
+}
+
+func (e *TaskError) Error() string {
+	return fmt.Sprintf("task %q: %v", e.Task, e.Err)
+}
+
+func (e *TaskError) Unwrap() error {
+	return e.Err
+}
+
+type Option func(*Group)
+
+// WithLimit bounds the number of tasks running at the same time.
+func WithLimit(limit int) Option {
+	return func(g *Group) {
+		if limit > 0 {
+			g.semaphore = make(chan struct{}, limit)
+		}
+	}
+}
+
+// WithCancelOnError cancels the group context on the first error.
+func WithCancelOnError() Option {
+	return WithCancelAfter(1)
+}
+
+// WithCancelAfter cancels the group context once count tasks have failed.
+func WithCancelAfter(count int) Option {
+	return func(g *Group) {
+		g.cancelAfter = count
+	}
+}
+
+// Group runs tasks in goroutines and, unlike the first-error-wins approach,
+// keeps every failure.
+type Group struct {
+	cancel      context.CancelCauseFunc
+	semaphore   chan struct{}
+	cancelAfter int
+
+	wg     sync.WaitGroup
+	mutex  sync.Mutex
+	tasks  int
+	errors []*TaskError
+}
+
+func New(ctx context.Context, options ...Option) (*Group, context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	group := &Group{cancel: cancel}
+	for _, option := range options {
+		option(group)
+	}
+	return group, ctx
+}
+
+// Go starts the task, blocking while the concurrency limit is reached.
+func (g *Group) Go(name string, task func() error) {
+	g.mutex.Lock()
+	index := g.tasks
+	g.tasks++
+	g.mutex.Unlock()
+
+	if g.semaphore != nil {
+		g.semaphore <- struct{}{}
+	}
+
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if g.semaphore != nil {
+			defer func() { <-g.semaphore }()
+		}
+
+		if err := task(); err != nil {
+			g.fail(&TaskError{Task: name, Index: index, Err: err})
+		}
+	}()
+}
+
+func (g *Group) fail(err *TaskError) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	g.errors = append(g.errors, err)
+	if g.cancelAfter > 0 && len(g.errors) == g.cancelAfter {
+		g.cancel(err)
+	}
+}
+
+// Wait blocks until all tasks finish and returns their errors as a *multierror.MultiError
+// ordered by task index, or nil when every task succeeded.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	g.cancel(context.Canceled)
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	slices.SortFunc(g.errors, func(lhs, rhs *TaskError) int {
+		return lhs.Index - rhs.Index
+	})
+
+	var result *multierror.MultiError
+	for _, err := range g.errors {
+		result = multierror.Append(result, err)
+	}
+	return result.ErrorOrNil()
+}
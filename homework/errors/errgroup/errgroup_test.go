@@ -0,0 +1,125 @@
+package errgroup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang_course/homework/errors/multierror"
+)
+
+// go test -v -race .
+
+var errTask = errors.New("task failed")
+
+func TestCollectsAllErrors(t *testing.T) {
+	group, _ := New(context.Background())
+	for idx := 0; idx < 10; idx++ {
+		group.Go(fmt.Sprintf("task-%d", idx), func() error {
+			if idx%3 == 0 {
+				return fmt.Errorf("%w: %d", errTask, idx)
+			}
+			return nil
+		})
+	}
+
+	err := group.Wait()
+	require.Error(t, err)
+
+	var multiErr *multierror.MultiError
+	require.True(t, errors.As(err, &multiErr))
+	require.Equal(t, 4, multiErr.Len())
+	assert.True(t, errors.Is(err, errTask))
+
+	for position, idx := range []int{0, 3, 6, 9} {
+		var taskErr *TaskError
+		require.True(t, errors.As(multiErr.Errors()[position], &taskErr))
+		assert.Equal(t, fmt.Sprintf("task-%d", idx), taskErr.Task)
+		assert.Equal(t, idx, taskErr.Index)
+		assert.EqualError(t, taskErr, fmt.Sprintf("task %q: task failed: %d", taskErr.Task, idx))
+	}
+}
+
+func TestNoErrors(t *testing.T) {
+	group, ctx := New(context.Background())
+	group.Go("ok", func() error { return nil })
+	assert.NoError(t, group.Wait())
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+}
+
+func TestLimit(t *testing.T) {
+	const limit = 3
+	var running, peak atomic.Int32
+
+	group, _ := New(context.Background(), WithLimit(limit))
+	for idx := 0; idx < 20; idx++ {
+		group.Go(fmt.Sprint(idx), func() error {
+			current := running.Add(1)
+			for {
+				observed := peak.Load()
+				if current <= observed || peak.CompareAndSwap(observed, current) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			running.Add(-1)
+			return nil
+		})
+	}
+
+	require.NoError(t, group.Wait())
+	assert.LessOrEqual(t, peak.Load(), int32(limit))
+}
+
+func TestCancelOnError(t *testing.T) {
+	group, ctx := New(context.Background(), WithCancelOnError())
+	group.Go("failing", func() error {
+		return errTask
+	})
+	group.Go("waiting", func() error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	err := group.Wait()
+	assert.ErrorIs(t, err, errTask)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorIs(t, context.Cause(ctx), errTask)
+}
+
+func TestCancelAfter(t *testing.T) {
+	group, ctx := New(context.Background(), WithCancelAfter(2))
+	release := make(chan struct{})
+
+	group.Go("first", func() error {
+		return errTask
+	})
+	group.Go("check", func() error {
+		time.Sleep(10 * time.Millisecond)
+		if ctx.Err() != nil {
+			return errors.New("cancelled after a single error")
+		}
+		close(release)
+		return nil
+	})
+	group.Go("second", func() error {
+		<-release
+		return errTask
+	})
+	group.Go("waiting", func() error {
+		<-ctx.Done()
+		return nil
+	})
+
+	err := group.Wait()
+	var multiErr *multierror.MultiError
+	require.True(t, errors.As(err, &multiErr))
+	assert.Equal(t, 2, multiErr.Len())
+	assert.ErrorIs(t, context.Cause(ctx), errTask)
+}
@@ -0,0 +1,132 @@
+package localize
+
+import (
+	"strings"
+
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/message/catalog"
+
+	"golang_course/homework/errors/errcode"
+	"golang_course/homework/errors/multierror"
+)
+
+const (
+	errorsKey = "errors.count"
+	headerKey = "errors.header"
+)
+
+// Localizer keeps a message catalog, error codes are looked up by their
+// names and fall back to the English code message.
+type Localizer struct {
+	builder *catalog.Builder
+}
+
+// New returns a Localizer with English and Russian plural forms for error counts.
+func New() *Localizer {
+	builder := catalog.NewBuilder(catalog.Fallback(language.English))
+
+	builder.Set(language.English, errorsKey, plural.Selectf(1, "%d",
+		plural.One, "%d error",
+		plural.Other, "%d errors",
+	))
+	builder.Set(language.English, headerKey, plural.Selectf(1, "%d",
+		plural.One, "%d error occurred:",
+		plural.Other, "%d errors occurred:",
+	))
+
+	builder.Set(language.Russian, errorsKey, plural.Selectf(1, "%d",
+		plural.One, "%d ошибка",
+		plural.Few, "%d ошибки",
+		plural.Other, "%d ошибок",
+	))
+	builder.Set(language.Russian, headerKey, plural.Selectf(1, "%d",
+		plural.One, "Произошла %d ошибка:",
+		plural.Few, "Произошли %d ошибки:",
+		plural.Other, "Произошло %d ошибок:",
+	))
+	builder.SetString(language.Russian, errcode.Unknown.Name(), "неизвестная ошибка")
+
+	return &Localizer{builder: builder}
+}
+
+var Default = New()
+
+// Set adds a translation of the code message.
+func (l *Localizer) Set(tag language.Tag, code *errcode.Code, text string) error {
+	return l.builder.SetString(tag, code.Name(), text)
+}
+
+// Printer matches tag against the catalog languages, unsupported ones get English.
+func (l *Localizer) Printer(tag language.Tag) *message.Printer {
+	tag, _, _ = l.builder.Matcher().Match(tag)
+	return message.NewPrinter(tag, message.Catalog(l.builder))
+}
+
+// Errors returns the count with the plural form of the word, "2 errors" or "2 ошибки".
+func (l *Localizer) Errors(tag language.Tag, count int) string {
+	return l.Printer(tag).Sprintf(errorsKey, count)
+}
+
+// Render translates every coded error in the chain. Text added by wrappers
+// like fmt.Errorf is kept as is, details given to errcode Errorf are replaced
+// by the translated code message. MultiErrors get a translated header.
+func (l *Localizer) Render(tag language.Tag, err error) string {
+	if err == nil {
+		return ""
+	}
+	return l.render(l.Printer(tag), err)
+}
+
+// Format returns a multierror.FormatFunc with the layout of
+// multierror.DefaultFormat in the requested language.
+func (l *Localizer) Format(tag language.Tag) multierror.FormatFunc {
+	return func(errs []error) string {
+		return l.format(l.Printer(tag), errs)
+	}
+}
+
+func (l *Localizer) render(printer *message.Printer, err error) string {
+	switch typed := err.(type) {
+	case *errcode.Code:
+		return translate(printer, typed)
+	case *errcode.Error:
+		text := translate(printer, typed.Code())
+		if cause := typed.Unwrap(); cause != nil {
+			text += ": " + l.render(printer, cause)
+		}
+		return text
+	case interface{ Unwrap() []error }:
+		if errs, ok := multierror.List(err); ok {
+			return l.format(printer, errs)
+		}
+		return err.Error()
+	case interface{ Unwrap() error }:
+		text := err.Error()
+		cause := typed.Unwrap()
+		if cause == nil {
+			return text
+		}
+
+		if prefix, found := strings.CutSuffix(text, cause.Error()); found {
+			return prefix + l.render(printer, cause)
+		}
+		return text
+	}
+	return err.Error()
+}
+
+func (l *Localizer) format(printer *message.Printer, errs []error) string {
+	var builder strings.Builder
+	builder.WriteString(printer.Sprintf(headerKey, len(errs)) + "\n")
+	for _, err := range errs {
+		builder.WriteString("\t* " + l.render(printer, err) + "\n")
+	}
+	builder.WriteString("\n")
+	return builder.String()
+}
+
+func translate(printer *message.Printer, code *errcode.Code) string {
+	return printer.Sprintf(message.Key(code.Name(), strings.ReplaceAll(code.Message(), "%", "%%")))
+}
@@ -0,0 +1,181 @@
+package localize
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+
+	"golang_course/homework/errors/errcode"
+	"golang_course/homework/errors/multierror"
+	"golang_course/homework/errors/stackerr"
+)
+
+// go test -v .
+
+var (
+	ZeroNumberErr = errcode.MustRegister(errcode.Definition{
+		Name:     "localize.zero_number",
+		Message:  "division by zero",
+		Category: errcode.CategoryInvalidInput,
+	})
+	StorageErr = errcode.MustRegister(errcode.Definition{
+		Name:     "localize.storage",
+		Message:  "storage is unavailable",
+		Category: errcode.CategoryUnavailable,
+	})
+	DiskErr = errcode.MustRegister(errcode.Definition{
+		Name:    "localize.disk",
+		Message: "disk is 100% full",
+	})
+)
+
+func newLocalizer(t *testing.T) *Localizer {
+	t.Helper()
+	localizer := New()
+	require.NoError(t, localizer.Set(language.Russian, ZeroNumberErr, "деление на ноль"))
+	require.NoError(t, localizer.Set(language.Russian, StorageErr, "хранилище недоступно"))
+	return localizer
+}
+
+func TestErrors(t *testing.T) {
+	localizer := New()
+
+	tests := map[string]struct {
+		tag    language.Tag
+		counts []int
+		result []string
+	}{
+		"english": {
+			tag:    language.English,
+			counts: []int{0, 1, 2, 5, 21},
+			result: []string{"0 errors", "1 error", "2 errors", "5 errors", "21 errors"},
+		},
+		"russian": {
+			tag:    language.Russian,
+			counts: []int{0, 1, 2, 4, 5, 11, 21, 22, 25, 101},
+			result: []string{
+				"0 ошибок", "1 ошибка", "2 ошибки", "4 ошибки", "5 ошибок",
+				"11 ошибок", "21 ошибка", "22 ошибки", "25 ошибок", "101 ошибка",
+			},
+		},
+		"regional russian": {
+			tag:    language.MustParse("ru-RU"),
+			counts: []int{1, 3},
+			result: []string{"1 ошибка", "3 ошибки"},
+		},
+		"fallback to english": {
+			tag:    language.German,
+			counts: []int{1, 3},
+			result: []string{"1 error", "3 errors"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var result []string
+			for _, count := range test.counts {
+				result = append(result, localizer.Errors(test.tag, count))
+			}
+			assert.Equal(t, test.result, result)
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	localizer := newLocalizer(t)
+
+	tests := map[string]struct {
+		err     error
+		english string
+		russian string
+	}{
+		"nil": {},
+		"code": {
+			err:     ZeroNumberErr.New(),
+			english: "division by zero",
+			russian: "деление на ноль",
+		},
+		"bare code": {
+			err:     fmt.Errorf("divide: %w", ZeroNumberErr),
+			english: "divide: division by zero",
+			russian: "divide: деление на ноль",
+		},
+		"wrapped chain": {
+			err:     stackerr.Wrap(StorageErr.Wrap(ZeroNumberErr.New()), "save"),
+			english: "save: storage is unavailable: division by zero",
+			russian: "save: хранилище недоступно: деление на ноль",
+		},
+		"uncoded cause": {
+			err:     StorageErr.Wrap(io.EOF),
+			english: "storage is unavailable: EOF",
+			russian: "хранилище недоступно: EOF",
+		},
+		"missing translation": {
+			err:     DiskErr.New(),
+			english: "disk is 100% full",
+			russian: "disk is 100% full",
+		},
+		"plain error": {
+			err:     io.EOF,
+			english: "EOF",
+			russian: "EOF",
+		},
+		"unknown code": {
+			err:     errcode.Unknown.New(),
+			english: "unknown error",
+			russian: "неизвестная ошибка",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.english, localizer.Render(language.English, test.err))
+			assert.Equal(t, test.russian, localizer.Render(language.Russian, test.err))
+		})
+	}
+}
+
+func TestRenderMultiError(t *testing.T) {
+	localizer := newLocalizer(t)
+
+	single := multierror.Append(nil, ZeroNumberErr.New())
+	assert.Equal(t, single.Error(), localizer.Render(language.English, single))
+	assert.Equal(t, "Произошла 1 ошибка:\n\t* деление на ноль\n\n", localizer.Render(language.Russian, single))
+
+	err := fmt.Errorf("batch: %w", multierror.Append(ZeroNumberErr.New(), StorageErr.Wrap(io.EOF), errors.New("disk")))
+	assert.Equal(t,
+		"batch: 3 errors occurred:\n\t* division by zero\n\t* storage is unavailable: EOF\n\t* disk\n\n",
+		localizer.Render(language.English, err))
+	assert.Equal(t,
+		"batch: Произошли 3 ошибки:\n\t* деление на ноль\n\t* хранилище недоступно: EOF\n\t* disk\n\n",
+		localizer.Render(language.Russian, err))
+
+	many := multierror.Append(nil, io.EOF, io.EOF, io.EOF, io.EOF, io.EOF)
+	assert.Contains(t, localizer.Render(language.Russian, many), "Произошло 5 ошибок:\n")
+
+	joined := errors.Join(ZeroNumberErr.New(), io.EOF)
+	assert.Equal(t, "Произошли 2 ошибки:\n\t* деление на ноль\n\t* EOF\n\n", localizer.Render(language.Russian, joined))
+
+	// errors wrapping several causes with their own text are not lists
+	wrapper := fmt.Errorf("load config: %w; %w", io.EOF, io.ErrClosedPipe)
+	assert.Equal(t, wrapper.Error(), localizer.Render(language.Russian, wrapper))
+	coded := StorageErr.Errorf("read block %d: %w, close: %w", 7, io.EOF, io.ErrClosedPipe)
+	assert.Equal(t,
+		"хранилище недоступно: read block 7: EOF, close: io: read/write on closed pipe",
+		localizer.Render(language.Russian, coded))
+}
+
+func TestFormat(t *testing.T) {
+	localizer := newLocalizer(t)
+
+	err := multierror.Append(ZeroNumberErr.New(), io.EOF)
+	assert.Equal(t, multierror.DefaultFormat(err.Errors()), localizer.Format(language.English)(err.Errors()))
+
+	err.WithFormat(localizer.Format(language.Russian))
+	assert.EqualError(t, err, "Произошли 2 ошибки:\n\t* деление на ноль\n\t* EOF\n\n")
+}
@@ -0,0 +1,152 @@
+package multierror
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// FormatFunc renders the collected errors into the Error() message.
+type FormatFunc func(errs []error) string
+
+// MultiError collects several errors into one. It implements Unwrap() []error,
+// so errors.Is and errors.As look through every collected error.
+type MultiError struct {
+	errs   []error
+	format FormatFunc
+}
+
+// Append adds errs to err. If err is a *MultiError it is extended in place,
+// nested MultiErrors are flattened and nil errors are skipped.
+func Append(err error, errs ...error) *MultiError {
+	multiErr, ok := err.(*MultiError)
+	if !ok || multiErr == nil {
+		multiErr = &MultiError{}
+		multiErr.append(err)
+	}
+
+	for _, e := range errs {
+		multiErr.append(e)
+	}
+	return multiErr
+}
+
+func (e *MultiError) append(err error) {
+	switch err := err.(type) {
+	case nil:
+	case *MultiError:
+		if err == nil {
+			return
+		}
+		for _, nested := range err.errs {
+			e.append(nested)
+		}
+	default:
+		e.errs = append(e.errs, err)
+	}
+}
+
+func (e *MultiError) Error() string {
+	if e.format != nil {
+		return e.format(e.errs)
+	}
+	return DefaultFormat(e.errs)
+}
+
+// Format prints every error with %+v when the plus flag is set,
+// so stack traces of the collected errors are not lost.
+func (e *MultiError) Format(state fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && state.Flag('+'):
+		if len(e.errs) == 1 {
+			io.WriteString(state, "1 error occurred:")
+		} else {
+			fmt.Fprintf(state, "%d errors occurred:", len(e.errs))
+		}
+		for _, err := range e.errs {
+			details := strings.ReplaceAll(fmt.Sprintf("%+v", err), "\n", "\n\t  ")
+			io.WriteString(state, "\n\t* "+details)
+		}
+	case verb == 'q':
+		fmt.Fprintf(state, "%q", e.Error())
+	default:
+		io.WriteString(state, e.Error())
+	}
+}
+
+func (e *MultiError) Unwrap() []error {
+	return e.errs
+}
+
+// List returns the errors of a *MultiError or of an errors.Join result.
+// Other errors with Unwrap() []error, like fmt.Errorf with several %w,
+// have a message of their own and are not lists.
+func List(err error) ([]error, bool) {
+	switch typed := err.(type) {
+	case *MultiError:
+		return typed.Errors(), true
+	case interface{ Unwrap() []error }:
+		errs := typed.Unwrap()
+		texts := make([]string, len(errs))
+		for idx, err := range errs {
+			texts[idx] = err.Error()
+		}
+		// errors.Join only puts the messages on separate lines
+		if err.Error() == strings.Join(texts, "\n") {
+			return errs, true
+		}
+	}
+	return nil, false
+}
+
+// Errors returns a copy of the collected errors.
+func (e *MultiError) Errors() []error {
+	return append([]error(nil), e.errs...)
+}
+
+func (e *MultiError) Len() int {
+	if e == nil {
+		return 0
+	}
+	return len(e.errs)
+}
+
+// ErrorOrNil returns nil when nothing was collected, so the result
+// can be returned as a plain error without the typed nil trap.
+func (e *MultiError) ErrorOrNil() error {
+	if e.Len() == 0 {
+		return nil
+	}
+	return e
+}
+
+// WithFormat replaces the message format and returns the same MultiError.
+func (e *MultiError) WithFormat(format FormatFunc) *MultiError {
+	e.format = format
+	return e
+}
+
+func DefaultFormat(errs []error) string {
+	if len(errs) == 1 {
+		return fmt.Sprintf("1 error occurred:\n\t* %s\n\n", errs[0])
+	}
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "%d errors occurred:\n", len(errs))
+	for _, err := range errs {
+		fmt.Fprintf(&builder, "\t* %s\n", err)
+	}
+	builder.WriteString("\n")
+	return builder.String()
+}
+
+// LegacyFormat keeps the original single line layout of the homework MultiError.
+func LegacyFormat(errs []error) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "%d errors occured:\n", len(errs))
+	for _, err := range errs {
+		builder.WriteString("\t* " + err.Error())
+	}
+	builder.WriteString("\n")
+	return builder.String()
+}
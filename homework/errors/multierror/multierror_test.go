@@ -0,0 +1,141 @@
+package multierror
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type RandomError struct {
+	code int
+}
+
+func (re RandomError) Error() string {
+	return fmt.Sprintf("error with code %d", re.code)
+}
+
+func TestAppend(t *testing.T) {
+	tests := map[string]struct {
+		err    error
+		errs   []error
+		result []error
+	}{
+		"nil base": {
+			errs:   []error{io.EOF},
+			result: []error{io.EOF},
+		},
+		"plain base": {
+			err:    io.EOF,
+			errs:   []error{fs.ErrNotExist},
+			result: []error{io.EOF, fs.ErrNotExist},
+		},
+		"nil errors skipped": {
+			err:    nil,
+			errs:   []error{nil, io.EOF, nil},
+			result: []error{io.EOF},
+		},
+		"nested flattening": {
+			err:    Append(io.EOF, Append(fs.ErrExist, Append(nil, fs.ErrClosed))),
+			errs:   []error{Append(fs.ErrNotExist)},
+			result: []error{io.EOF, fs.ErrExist, fs.ErrClosed, fs.ErrNotExist},
+		},
+		"typed nil multierror": {
+			err:    (*MultiError)(nil),
+			errs:   []error{io.EOF},
+			result: []error{io.EOF},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			result := Append(test.err, test.errs...)
+			assert.Equal(t, test.result, result.Errors())
+			assert.Equal(t, test.result, result.Unwrap())
+		})
+	}
+}
+
+func TestIsAs(t *testing.T) {
+	err := Append(nil, io.EOF, fmt.Errorf("wrapped: %w", RandomError{code: 42}))
+
+	assert.True(t, errors.Is(err, io.EOF))
+	assert.False(t, errors.Is(err, io.ErrUnexpectedEOF))
+	// the old implementation compared messages, so equal texts matched
+	assert.False(t, errors.Is(err, errors.New("EOF")))
+
+	var target RandomError
+	require.True(t, errors.As(err, &target))
+	assert.Equal(t, 42, target.code)
+
+	var pathErr *fs.PathError
+	assert.False(t, errors.As(err, &pathErr))
+}
+
+func TestJoinInterop(t *testing.T) {
+	joined := errors.Join(fs.ErrNotExist, RandomError{code: 7})
+	err := Append(io.EOF, joined)
+	assert.Equal(t, 2, err.Len())
+	assert.True(t, errors.Is(err, fs.ErrNotExist))
+
+	var target RandomError
+	require.True(t, errors.As(err, &target))
+	assert.Equal(t, 7, target.code)
+
+	outer := errors.Join(Append(nil, io.ErrClosedPipe), io.ErrShortWrite)
+	assert.True(t, errors.Is(outer, io.ErrClosedPipe))
+	assert.True(t, errors.Is(fmt.Errorf("context: %w", err), fs.ErrNotExist))
+}
+
+func TestList(t *testing.T) {
+	tests := map[string]struct {
+		err  error
+		errs []error
+		ok   bool
+	}{
+		"multierror": {err: Append(io.EOF, io.ErrClosedPipe), errs: []error{io.EOF, io.ErrClosedPipe}, ok: true},
+		"join":       {err: errors.Join(io.EOF, io.ErrClosedPipe), errs: []error{io.EOF, io.ErrClosedPipe}, ok: true},
+		"wrapper":    {err: fmt.Errorf("load config: %w; %w", io.EOF, io.ErrClosedPipe)},
+		"single":     {err: fmt.Errorf("load config: %w", io.EOF)},
+		"plain":      {err: io.EOF},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			errs, ok := List(test.err)
+			assert.Equal(t, test.ok, ok)
+			assert.Equal(t, test.errs, errs)
+		})
+	}
+}
+
+func TestErrorOrNil(t *testing.T) {
+	var multiErr *MultiError
+	assert.NoError(t, multiErr.ErrorOrNil())
+	assert.NoError(t, Append(nil).ErrorOrNil())
+	assert.Error(t, Append(nil, io.EOF).ErrorOrNil())
+}
+
+func TestFormat(t *testing.T) {
+	err := Append(errors.New("error 1"), errors.New("error 2"))
+	assert.EqualError(t, err, "2 errors occurred:\n\t* error 1\n\t* error 2\n\n")
+	assert.EqualError(t, Append(nil, errors.New("error 1")), "1 error occurred:\n\t* error 1\n\n")
+
+	err.WithFormat(LegacyFormat)
+	assert.EqualError(t, err, "2 errors occured:\n\t* error 1\t* error 2\n")
+
+	err.WithFormat(func(errs []error) string { return fmt.Sprint(len(errs)) })
+	assert.EqualError(t, err, "2")
+}
+
+func TestVerboseFormat(t *testing.T) {
+	err := Append(errors.New("error 1"), fmt.Errorf("wrapped: %w", errors.New("error 2")))
+	assert.Equal(t, err.Error(), fmt.Sprintf("%v", err))
+	assert.Equal(t, err.Error(), fmt.Sprintf("%s", err))
+	assert.Equal(t, "2 errors occurred:\n\t* error 1\n\t* wrapped: error 2", fmt.Sprintf("%+v", err))
+	assert.Equal(t, "1 error occurred:\n\t* error 1", fmt.Sprintf("%+v", Append(errors.New("error 1"))))
+}
@@ -0,0 +1,127 @@
+package optional
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+)
+
+var ErrEmpty = errors.New("optional value is empty")
+
+// Option holds a value or nothing, the zero Option is empty.
+type Option[T any] struct {
+	value   T
+	present bool
+}
+
+func Some[T any](value T) Option[T] {
+	return Option[T]{value: value, present: true}
+}
+
+func None[T any]() Option[T] {
+	return Option[T]{}
+}
+
+// FromPointer treats nil as empty.
+func FromPointer[T any](value *T) Option[T] {
+	if value == nil {
+		return None[T]()
+	}
+	return Some(*value)
+}
+
+func (o Option[T]) IsSome() bool {
+	return o.present
+}
+
+func (o Option[T]) IsNone() bool {
+	return !o.present
+}
+
+func (o Option[T]) Get() (T, bool) {
+	return o.value, o.present
+}
+
+// Unwrap panics with ErrEmpty when there is no value.
+func (o Option[T]) Unwrap() T {
+	if !o.present {
+		panic(ErrEmpty)
+	}
+	return o.value
+}
+
+func (o Option[T]) OrElse(value T) T {
+	if !o.present {
+		return value
+	}
+	return o.value
+}
+
+// OrElseGet calls fn only for an empty option.
+func (o Option[T]) OrElseGet(fn func() T) T {
+	if !o.present {
+		return fn()
+	}
+	return o.value
+}
+
+// Result converts to (T, error) world, err is used for an empty option.
+func (o Option[T]) Result(err error) Result[T] {
+	if !o.present {
+		return Err[T](err)
+	}
+	return Ok(o.value)
+}
+
+func Map[T, U any](o Option[T], fn func(T) U) Option[U] {
+	if !o.present {
+		return None[U]()
+	}
+	return Some(fn(o.value))
+}
+
+func FlatMap[T, U any](o Option[T], fn func(T) Option[U]) Option[U] {
+	if !o.present {
+		return None[U]()
+	}
+	return fn(o.value)
+}
+
+// MarshalJSON writes null for an empty option.
+func (o Option[T]) MarshalJSON() ([]byte, error) {
+	if !o.present {
+		return []byte("null"), nil
+	}
+	return json.Marshal(o.value)
+}
+
+func (o *Option[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*o = None[T]()
+		return nil
+	}
+
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*o = Some(value)
+	return nil
+}
+
+// Scan implements sql.Scanner, NULL becomes an empty option.
+func (o *Option[T]) Scan(src any) error {
+	var null sql.Null[T]
+	if err := null.Scan(src); err != nil {
+		return err
+	}
+	*o = Option[T]{value: null.V, present: null.Valid}
+	return nil
+}
+
+// Value implements driver.Valuer, an empty option is stored as NULL.
+func (o Option[T]) Value() (driver.Value, error) {
+	return sql.Null[T]{V: o.value, Valid: o.present}.Value()
+}
@@ -0,0 +1,199 @@
+package optional
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// go test -v .
+
+func divide(lhs, rhs int) Option[int] {
+	if rhs == 0 {
+		return None[int]()
+	}
+	return Some(lhs / rhs)
+}
+
+func TestOption(t *testing.T) {
+	tests := map[string]struct {
+		option   Option[int]
+		present  bool
+		orElse   int
+		doubled  Option[int]
+		positive Option[string]
+	}{
+		"value": {
+			option:   divide(100, 5),
+			present:  true,
+			orElse:   20,
+			doubled:  Some(40),
+			positive: Some("20"),
+		},
+		"negative value": {
+			option:   divide(-100, 5),
+			present:  true,
+			orElse:   -20,
+			doubled:  Some(-40),
+			positive: None[string](),
+		},
+		"empty": {
+			option:   divide(100, 0),
+			orElse:   -1,
+			doubled:  None[int](),
+			positive: None[string](),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.present, test.option.IsSome())
+			assert.Equal(t, !test.present, test.option.IsNone())
+			assert.Equal(t, test.orElse, test.option.OrElse(-1))
+			assert.Equal(t, test.orElse, test.option.OrElseGet(func() int { return -1 }))
+			assert.Equal(t, test.doubled, Map(test.option, func(value int) int { return value * 2 }))
+			assert.Equal(t, test.positive, FlatMap(test.option, func(value int) Option[string] {
+				if value < 0 {
+					return None[string]()
+				}
+				return Some(strconv.Itoa(value))
+			}))
+		})
+	}
+}
+
+func TestOptionUnwrap(t *testing.T) {
+	assert.Equal(t, 20, divide(100, 5).Unwrap())
+	assert.PanicsWithError(t, ErrEmpty.Error(), func() { divide(100, 0).Unwrap() })
+
+	called := false
+	assert.Equal(t, 20, divide(100, 5).OrElseGet(func() int { called = true; return 0 }))
+	assert.False(t, called)
+
+	number := 7
+	assert.Equal(t, Some(7), FromPointer(&number))
+	assert.Equal(t, None[int](), FromPointer[int](nil))
+}
+
+func TestResult(t *testing.T) {
+	result := NewResult(strconv.Atoi("42"))
+	require.True(t, result.IsOk())
+	assert.Equal(t, 42, result.Unwrap())
+	assert.Equal(t, Some(42), result.Option())
+
+	value, err := MapResult(result, func(value int) int { return value + 1 }).Get()
+	assert.NoError(t, err)
+	assert.Equal(t, 43, value)
+
+	failed := NewResult(strconv.Atoi("forty two"))
+	require.True(t, failed.IsErr())
+	assert.ErrorIs(t, failed.Err(), strconv.ErrSyntax)
+	assert.Equal(t, 0, failed.OrElse(0))
+	assert.Equal(t, -1, failed.OrElseGet(func(error) int { return -1 }))
+	assert.Equal(t, None[int](), failed.Option())
+	assert.Panics(t, func() { failed.Unwrap() })
+
+	assert.ErrorIs(t, Err[int](nil).Err(), ErrNilError)
+}
+
+func TestResultChaining(t *testing.T) {
+	parse := func(text string) Result[int] {
+		return NewResult(strconv.Atoi(text))
+	}
+	half := func(value int) Result[int] {
+		if value%2 != 0 {
+			return Err[int](io.ErrShortBuffer)
+		}
+		return Ok(value / 2)
+	}
+
+	value, err := FlatMapResult(parse("42"), half).Get()
+	assert.NoError(t, err)
+	assert.Equal(t, 21, value)
+
+	_, err = FlatMapResult(parse("43"), half).Get()
+	assert.ErrorIs(t, err, io.ErrShortBuffer)
+
+	_, err = FlatMapResult(parse("x"), half).Get()
+	assert.ErrorIs(t, err, strconv.ErrSyntax)
+
+	text, err := Then(parse("12"), func(value int) (string, error) {
+		return strconv.Itoa(value * 10), nil
+	}).Get()
+	assert.NoError(t, err)
+	assert.Equal(t, "120", text)
+
+	_, err = divide(1, 0).Result(io.EOF).Get()
+	assert.ErrorIs(t, err, io.EOF)
+	assert.Equal(t, Ok(5), divide(10, 2).Result(io.EOF))
+}
+
+type UserDTO struct {
+	Name  string         `json:"name"`
+	Age   Option[int]    `json:"age"`
+	Email Option[string] `json:"email"`
+}
+
+func TestJSON(t *testing.T) {
+	data, err := json.Marshal(UserDTO{Name: "Bob", Age: Some(30)})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name":"Bob","age":30,"email":null}`, string(data))
+
+	tests := map[string]struct {
+		initial UserDTO
+		input   string
+		result  UserDTO
+	}{
+		"all fields": {
+			input:  `{"name":"Bob","age":30,"email":"bob@mail.com"}`,
+			result: UserDTO{Name: "Bob", Age: Some(30), Email: Some("bob@mail.com")},
+		},
+		"null field": {
+			initial: UserDTO{Age: Some(99)},
+			input:   `{"name":"Bob","age":null,"email":"bob@mail.com"}`,
+			result:  UserDTO{Name: "Bob", Email: Some("bob@mail.com")},
+		},
+		"missing field": {
+			initial: UserDTO{Email: Some("stale")},
+			input:   `{"name":"Bob","age":0}`,
+			result:  UserDTO{Name: "Bob", Age: Some(0), Email: Some("stale")},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			dto := test.initial
+			require.NoError(t, json.Unmarshal([]byte(test.input), &dto))
+			assert.Equal(t, test.result, dto)
+		})
+	}
+
+	var dto UserDTO
+	assert.Error(t, json.Unmarshal([]byte(`{"age":"thirty"}`), &dto))
+}
+
+func TestSQL(t *testing.T) {
+	value, err := Some(int64(42)).Value()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), value)
+
+	value, err = None[int64]().Value()
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+
+	var age Option[int]
+	require.NoError(t, age.Scan(int64(30)))
+	assert.Equal(t, Some(30), age)
+	require.NoError(t, age.Scan(nil))
+	assert.Equal(t, None[int](), age)
+
+	var name Option[string]
+	require.NoError(t, name.Scan([]byte("Bob")))
+	assert.Equal(t, Some("Bob"), name)
+
+	assert.Error(t, age.Scan("not a number"))
+}
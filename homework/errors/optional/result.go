@@ -0,0 +1,100 @@
+package optional
+
+import "errors"
+
+var ErrNilError = errors.New("result error is nil")
+
+// Result holds either a value or an error.
+type Result[T any] struct {
+	value T
+	err   error
+}
+
+func Ok[T any](value T) Result[T] {
+	return Result[T]{value: value}
+}
+
+// Err replaces a nil error with ErrNilError, so the result is never Ok by accident.
+func Err[T any](err error) Result[T] {
+	if err == nil {
+		err = ErrNilError
+	}
+	return Result[T]{err: err}
+}
+
+// NewResult builds a result from the usual (T, error) pair.
+func NewResult[T any](value T, err error) Result[T] {
+	if err != nil {
+		return Result[T]{err: err}
+	}
+	return Ok(value)
+}
+
+func (r Result[T]) IsOk() bool {
+	return r.err == nil
+}
+
+func (r Result[T]) IsErr() bool {
+	return r.err != nil
+}
+
+func (r Result[T]) Err() error {
+	return r.err
+}
+
+// Get returns the (T, error) pair back.
+func (r Result[T]) Get() (T, error) {
+	return r.value, r.err
+}
+
+// Unwrap panics with the result error.
+func (r Result[T]) Unwrap() T {
+	if r.err != nil {
+		panic(r.err)
+	}
+	return r.value
+}
+
+func (r Result[T]) OrElse(value T) T {
+	if r.err != nil {
+		return value
+	}
+	return r.value
+}
+
+func (r Result[T]) OrElseGet(fn func(error) T) T {
+	if r.err != nil {
+		return fn(r.err)
+	}
+	return r.value
+}
+
+// Option drops the error.
+func (r Result[T]) Option() Option[T] {
+	if r.err != nil {
+		return None[T]()
+	}
+	return Some(r.value)
+}
+
+func MapResult[T, U any](r Result[T], fn func(T) U) Result[U] {
+	if r.err != nil {
+		return Result[U]{err: r.err}
+	}
+	return Ok(fn(r.value))
+}
+
+func FlatMapResult[T, U any](r Result[T], fn func(T) Result[U]) Result[U] {
+	if r.err != nil {
+		return Result[U]{err: r.err}
+	}
+	return fn(r.value)
+}
+
+// Then chains functions written in the (T, error) style.
+func Then[T, U any](r Result[T], fn func(T) (U, error)) Result[U] {
+	if r.err != nil {
+		return Result[U]{err: r.err}
+	}
+	return NewResult(fn(r.value))
+}
@@ -0,0 +1,157 @@
+package panics
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+
+	"golang_course/homework/errors/multierror"
+	"golang_course/homework/errors/stackerr"
+)
+
+const maxDepth = 64
+
+// PanicError is a recovered panic, Stack starts at the function that panicked.
+type PanicError struct {
+	Value any
+	Stack stackerr.StackTrace
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the panic value when it is an error, so errors.Is
+// and errors.As see runtime errors and panics with sentinel errors.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// StackTrace makes the error a stackerr.StackTracer, so wrapping it with
+// stackerr keeps the stack of the panic.
+func (e *PanicError) StackTrace() stackerr.StackTrace {
+	return e.Stack
+}
+
+func (e *PanicError) Format(state fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		io.WriteString(state, e.Error())
+		if state.Flag('+') && len(e.Stack) != 0 {
+			io.WriteString(state, "\n"+e.Stack.String())
+		}
+	case 's':
+		io.WriteString(state, e.Error())
+	case 'q':
+		fmt.Fprintf(state, "%q", e.Error())
+	}
+}
+
+// Policy decides whether a recovered value has to be panicked again.
+type Policy func(value any) bool
+
+// RepanicRuntimeErrors lets faults like nil dereference or out of range index
+// crash the program, they mean a bug rather than a failed task. panic(nil)
+// is reported as *runtime.PanicNilError but is a regular panic call.
+func RepanicRuntimeErrors(value any) bool {
+	runtimeErr, ok := value.(runtime.Error)
+	if !ok {
+		return false
+	}
+
+	var panicNilErr *runtime.PanicNilError
+	return !errors.As(runtimeErr, &panicNilErr)
+}
+
+type Option func(*config)
+
+type config struct {
+	repanic Policy
+}
+
+// WithRepanic sets the policy, by default every panic becomes an error.
+func WithRepanic(policy Policy) Option {
+	return func(c *config) {
+		c.repanic = policy
+	}
+}
+
+// Recover has to be deferred directly, it stores a recovered panic into err:
+//
+//	defer panics.Recover(&err)
+func Recover(err *error, opts ...Option) {
+	value := recover()
+	if value == nil {
+		return
+	}
+
+	var cfg config
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.repanic != nil && cfg.repanic(value) {
+		panic(value)
+	}
+	*err = &PanicError{Value: value, Stack: panicStack()}
+}
+
+// Catch runs fn and returns its error or the recovered panic.
+func Catch(fn func() error, opts ...Option) (err error) {
+	defer Recover(&err, opts...)
+	return fn()
+}
+
+// SafeGo runs fn in a goroutine, the channel receives the result of Catch and is closed.
+func SafeGo(fn func() error, opts ...Option) <-chan error {
+	result := make(chan error, 1)
+	go func() {
+		defer close(result)
+		result <- Catch(fn, opts...)
+	}()
+	return result
+}
+
+// Wait drains the channels and returns all the errors as a MultiError or nil.
+func Wait(results ...<-chan error) error {
+	var multiErr *multierror.MultiError
+	for _, result := range results {
+		for err := range result {
+			if err != nil {
+				multiErr = multierror.Append(multiErr, err)
+			}
+		}
+	}
+	return multiErr.ErrorOrNil()
+}
+
+// panicStack skips the recovering code and the runtime panic machinery.
+func panicStack() stackerr.StackTrace {
+	var pcs [maxDepth]uintptr
+	count := runtime.Callers(1, pcs[:])
+
+	var trace stackerr.StackTrace
+	frames := runtime.CallersFrames(pcs[:count])
+	for {
+		frame, more := frames.Next()
+		trace = append(trace, stackerr.Frame{Function: frame.Function, File: frame.File, Line: frame.Line})
+		if !more {
+			break
+		}
+	}
+
+	for idx, frame := range trace {
+		if frame.Function == "runtime.gopanic" {
+			trace = trace[idx+1:]
+			break
+		}
+	}
+	// runtime faults go through runtime.panicmem, runtime.sigpanic and alike
+	for len(trace) != 0 && strings.HasPrefix(trace[0].Function, "runtime.") {
+		trace = trace[1:]
+	}
+	return trace
+}
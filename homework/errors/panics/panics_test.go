@@ -0,0 +1,167 @@
+package panics
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang_course/homework/errors/multierror"
+	"golang_course/homework/errors/stackerr"
+)
+
+// go test -v .
+
+type node struct {
+	next *node
+}
+
+func process() error {
+	panic("error from process")
+}
+
+func dereference() error {
+	var head *node
+	_ = head.next
+	return nil
+}
+
+func panicNil() error {
+	panic(nil)
+}
+
+func TestCatch(t *testing.T) {
+	tests := map[string]struct {
+		task    func() error
+		message string
+		target  error
+	}{
+		"no panic": {
+			task: func() error { return nil },
+		},
+		"returned error": {
+			task:    func() error { return io.EOF },
+			message: "EOF",
+			target:  io.EOF,
+		},
+		"string value": {
+			task:    process,
+			message: "panic: error from process",
+		},
+		"error value": {
+			task:    func() error { panic(fmt.Errorf("wrapped: %w", io.ErrUnexpectedEOF)) },
+			message: "panic: wrapped: unexpected EOF",
+			target:  io.ErrUnexpectedEOF,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := Catch(test.task)
+			if test.message == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.EqualError(t, err, test.message)
+			if test.target != nil {
+				assert.ErrorIs(t, err, test.target)
+			}
+		})
+	}
+}
+
+func TestPanicNil(t *testing.T) {
+	err := Catch(panicNil)
+	require.Error(t, err)
+
+	var panicErr *PanicError
+	require.True(t, errors.As(err, &panicErr))
+
+	var panicNilErr *runtime.PanicNilError
+	assert.True(t, errors.As(err, &panicNilErr))
+
+	// panic(nil) is a regular panic call, the policy keeps it as an error
+	assert.NotPanics(t, func() {
+		assert.ErrorAs(t, Catch(panicNil, WithRepanic(RepanicRuntimeErrors)), &panicNilErr)
+	})
+}
+
+func TestRepanic(t *testing.T) {
+	err := Catch(dereference)
+	var runtimeErr runtime.Error
+	require.True(t, errors.As(err, &runtimeErr))
+	assert.Contains(t, err.Error(), "nil pointer dereference")
+
+	assert.PanicsWithError(t, runtimeErr.Error(), func() {
+		_ = Catch(dereference, WithRepanic(RepanicRuntimeErrors))
+	})
+	assert.PanicsWithValue(t, "error from process", func() {
+		_ = Catch(process, WithRepanic(func(any) bool { return true }))
+	})
+	assert.NotPanics(t, func() {
+		_ = Catch(process, WithRepanic(RepanicRuntimeErrors))
+	})
+}
+
+func TestStack(t *testing.T) {
+	err := Catch(dereference)
+
+	var tracer stackerr.StackTracer
+	require.True(t, errors.As(err, &tracer))
+	trace := tracer.StackTrace()
+	require.NotEmpty(t, trace)
+	assert.True(t, strings.HasSuffix(trace[0].Function, "panics.dereference"))
+
+	wrapped := stackerr.Wrap(err, "handle request")
+	require.True(t, errors.As(wrapped, &tracer))
+	assert.True(t, strings.HasSuffix(tracer.StackTrace()[0].Function, "panics.dereference"))
+
+	verbose := fmt.Sprintf("%+v", Catch(process))
+	lines := strings.Split(verbose, "\n")
+	require.Greater(t, len(lines), 2)
+	assert.Equal(t, "panic: error from process", lines[0])
+	assert.True(t, strings.HasSuffix(lines[1], "panics.process"))
+	assert.Contains(t, lines[2], "panics_test.go:")
+}
+
+func TestRecover(t *testing.T) {
+	callback := func(values []int) (sum int, err error) {
+		defer Recover(&err)
+		for idx := 0; idx <= len(values); idx++ {
+			sum += values[idx]
+		}
+		return sum, nil
+	}
+
+	_, err := callback([]int{1, 2, 3})
+	var runtimeErr runtime.Error
+	assert.ErrorAs(t, err, &runtimeErr)
+	assert.Contains(t, err.Error(), "index out of range")
+}
+
+func TestSafeGo(t *testing.T) {
+	err := Wait(
+		SafeGo(func() error { return nil }),
+		SafeGo(process),
+		SafeGo(func() error { return io.EOF }),
+		SafeGo(panicNil),
+	)
+	require.Error(t, err)
+
+	var multiErr *multierror.MultiError
+	require.True(t, errors.As(err, &multiErr))
+	assert.Equal(t, 3, multiErr.Len())
+	assert.ErrorIs(t, err, io.EOF)
+
+	var panicNilErr *runtime.PanicNilError
+	assert.ErrorAs(t, err, &panicNilErr)
+	assert.Contains(t, fmt.Sprintf("%+v", err), "panics.process")
+
+	assert.NoError(t, Wait(SafeGo(func() error { return nil })))
+}
@@ -0,0 +1,252 @@
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"golang_course/homework/errors/errcode"
+	"golang_course/homework/errors/multierror"
+	"golang_course/homework/errors/stackerr"
+)
+
+type Option func(*options)
+
+type options struct {
+	dedup bool
+	limit int
+}
+
+// WithDedup merges identical errors of the same join into one entry with a count.
+func WithDedup() Option {
+	return func(o *options) {
+		o.dedup = true
+	}
+}
+
+// WithLimit keeps only the first limit entries of every join.
+func WithLimit(limit int) Option {
+	return func(o *options) {
+		o.limit = limit
+	}
+}
+
+type Frame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// Node is one level of an error chain. Message holds only the text added
+// at this level, joins get an "N errors" message and their errors as causes.
+// Count is set only when deduplication merged several equal errors.
+type Node struct {
+	Message string  `json:"message"`
+	Code    string  `json:"code,omitempty"`
+	Count   int     `json:"count,omitempty"`
+	Stack   []Frame `json:"stack,omitempty"`
+	Causes  []*Node `json:"causes,omitempty"`
+	Omitted int     `json:"omitted,omitempty"`
+}
+
+// Build turns the error chain into a tree. A stack trace is attached to the
+// deepest error that provides it, so wrappers do not repeat the same stack.
+func Build(err error, opts ...Option) *Node {
+	if err == nil {
+		return nil
+	}
+
+	var cfg options
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	node, _ := build(err, &cfg)
+	return node
+}
+
+func build(err error, cfg *options) (*Node, bool) {
+	node := &Node{Message: err.Error()}
+	switch typed := err.(type) {
+	case *errcode.Error:
+		node.Code = typed.Code().Name()
+	case *errcode.Code:
+		node.Code = typed.Name()
+	}
+
+	hasStack := false
+	switch typed := err.(type) {
+	case interface{ Unwrap() []error }:
+		// other wrappers of several errors keep their own text above the causes
+		if errs, ok := multierror.List(err); ok {
+			node.Message = countErrors(len(errs))
+		}
+		node.Causes, node.Omitted, hasStack = buildJoin(typed.Unwrap(), cfg)
+		return node, hasStack
+	case interface{ Unwrap() error }:
+		if cause := typed.Unwrap(); cause != nil {
+			var child *Node
+			child, hasStack = build(cause, cfg)
+			if prefix, found := strings.CutSuffix(node.Message, cause.Error()); found {
+				node.Message = strings.TrimSuffix(prefix, ": ")
+			}
+			// wrappers that add nothing are dropped from the tree
+			if node.Message == "" && node.Code == "" {
+				node = child
+			} else {
+				node.Causes = []*Node{child}
+			}
+		}
+	}
+
+	if !hasStack {
+		if tracer, ok := err.(stackerr.StackTracer); ok {
+			for _, frame := range tracer.StackTrace() {
+				node.Stack = append(node.Stack, Frame(frame))
+			}
+			hasStack = len(node.Stack) != 0
+		}
+	}
+	return node, hasStack
+}
+
+type entry struct {
+	err   error
+	count int
+}
+
+// group applies deduplication and the limit, omitted is the number of dropped errors.
+func group(errs []error, cfg *options) ([]*entry, int) {
+	var entries []*entry
+	seen := make(map[string]*entry)
+	for _, err := range errs {
+		key := err.Error()
+		if code := errcode.CodeOf(err); code != nil {
+			key = code.Name() + "\n" + key
+		}
+		if duplicate, found := seen[key]; cfg.dedup && found {
+			duplicate.count++
+			continue
+		}
+
+		seen[key] = &entry{err: err, count: 1}
+		entries = append(entries, seen[key])
+	}
+
+	omitted := 0
+	if cfg.limit > 0 && len(entries) > cfg.limit {
+		for _, entry := range entries[cfg.limit:] {
+			omitted += entry.count
+		}
+		entries = entries[:cfg.limit]
+	}
+	return entries, omitted
+}
+
+func buildJoin(errs []error, cfg *options) ([]*Node, int, bool) {
+	entries, omitted := group(errs, cfg)
+
+	causes := make([]*Node, 0, len(entries))
+	hasStack := false
+	for _, entry := range entries {
+		cause, causeStack := build(entry.err, cfg)
+		if entry.count > 1 {
+			cause.Count = entry.count
+		}
+		causes = append(causes, cause)
+		hasStack = hasStack || causeStack
+	}
+	return causes, omitted, hasStack
+}
+
+// Tree renders the error as an indented tree:
+//
+//	batch
+//	└── 2 errors
+//	    ├── storage is unavailable [storage.unavailable]
+//	    │   └── EOF
+//	    └── timeout (x3)
+func Tree(err error, opts ...Option) string {
+	node := Build(err, opts...)
+	if node == nil {
+		return ""
+	}
+
+	var builder strings.Builder
+	builder.WriteString(node.label() + "\n")
+	node.writeCauses(&builder, "")
+	return builder.String()
+}
+
+func (n *Node) label() string {
+	label := n.Message
+	if n.Code != "" {
+		label += " [" + n.Code + "]"
+	}
+	if n.Count > 1 {
+		label += fmt.Sprintf(" (x%d)", n.Count)
+	}
+	return label
+}
+
+func (n *Node) writeCauses(builder *strings.Builder, indent string) {
+	for idx, cause := range n.Causes {
+		last := idx == len(n.Causes)-1 && n.Omitted == 0
+		branch, nested := "├── ", "│   "
+		if last {
+			branch, nested = "└── ", "    "
+		}
+
+		builder.WriteString(indent + branch + cause.label() + "\n")
+		cause.writeCauses(builder, indent+nested)
+	}
+	if n.Omitted != 0 {
+		builder.WriteString(indent + "└── ... " + moreErrors(n.Omitted) + "\n")
+	}
+}
+
+func JSON(err error, opts ...Option) ([]byte, error) {
+	return json.Marshal(Build(err, opts...))
+}
+
+// List returns a multierror.FormatFunc with the layout of
+// multierror.DefaultFormat that supports deduplication and the limit.
+func List(opts ...Option) multierror.FormatFunc {
+	var cfg options
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return func(errs []error) string {
+		entries, omitted := group(errs, &cfg)
+
+		var builder strings.Builder
+		builder.WriteString(countErrors(len(errs)) + " occurred:\n")
+		for _, entry := range entries {
+			builder.WriteString("\t* " + entry.err.Error())
+			if entry.count > 1 {
+				fmt.Fprintf(&builder, " (x%d)", entry.count)
+			}
+			builder.WriteString("\n")
+		}
+		if omitted != 0 {
+			builder.WriteString("\t* ... " + moreErrors(omitted) + "\n")
+		}
+		builder.WriteString("\n")
+		return builder.String()
+	}
+}
+
+func countErrors(count int) string {
+	if count == 1 {
+		return "1 error"
+	}
+	return fmt.Sprintf("%d errors", count)
+}
+
+func moreErrors(count int) string {
+	if count == 1 {
+		return "and 1 more error"
+	}
+	return fmt.Sprintf("and %d more errors", count)
+}
@@ -0,0 +1,196 @@
+package render
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang_course/homework/errors/errcode"
+	"golang_course/homework/errors/multierror"
+	"golang_course/homework/errors/stackerr"
+)
+
+// go test -v .
+
+var StorageErr = errcode.MustRegister(errcode.Definition{
+	Name:     "render.storage",
+	Message:  "storage is unavailable",
+	Category: errcode.CategoryUnavailable,
+})
+
+var ErrTimeout = errors.New("timeout")
+
+func batchError() error {
+	return fmt.Errorf("batch: %w", multierror.Append(
+		StorageErr.Wrap(io.EOF),
+		ErrTimeout,
+		fmt.Errorf("row 7: %w", errors.Join(io.ErrUnexpectedEOF, ErrTimeout)),
+		ErrTimeout,
+		ErrTimeout,
+	))
+}
+
+func TestTree(t *testing.T) {
+	tests := map[string]struct {
+		err     error
+		options []Option
+		result  string
+	}{
+		"nil": {},
+		"plain": {
+			err:    io.EOF,
+			result: "EOF\n",
+		},
+		"wrap chain": {
+			err:    fmt.Errorf("save user: %w", StorageErr.Wrap(io.EOF)),
+			result: "save user\n└── storage is unavailable [render.storage]\n    └── EOF\n",
+		},
+		"nested joins": {
+			err: batchError(),
+			result: "batch\n" +
+				"└── 5 errors\n" +
+				"    ├── storage is unavailable [render.storage]\n" +
+				"    │   └── EOF\n" +
+				"    ├── timeout\n" +
+				"    ├── row 7\n" +
+				"    │   └── 2 errors\n" +
+				"    │       ├── unexpected EOF\n" +
+				"    │       └── timeout\n" +
+				"    ├── timeout\n" +
+				"    └── timeout\n",
+		},
+		"deduplication": {
+			err:     batchError(),
+			options: []Option{WithDedup()},
+			result: "batch\n" +
+				"└── 5 errors\n" +
+				"    ├── storage is unavailable [render.storage]\n" +
+				"    │   └── EOF\n" +
+				"    ├── timeout (x3)\n" +
+				"    └── row 7\n" +
+				"        └── 2 errors\n" +
+				"            ├── unexpected EOF\n" +
+				"            └── timeout\n",
+		},
+		"truncation": {
+			err:     batchError(),
+			options: []Option{WithLimit(2)},
+			result: "batch\n" +
+				"└── 5 errors\n" +
+				"    ├── storage is unavailable [render.storage]\n" +
+				"    │   └── EOF\n" +
+				"    ├── timeout\n" +
+				"    └── ... and 3 more errors\n",
+		},
+		"deduplication and truncation": {
+			err:     batchError(),
+			options: []Option{WithDedup(), WithLimit(1)},
+			result: "batch\n" +
+				"└── 5 errors\n" +
+				"    ├── storage is unavailable [render.storage]\n" +
+				"    │   └── EOF\n" +
+				"    └── ... and 4 more errors\n",
+		},
+		"wrapper of several errors": {
+			err: fmt.Errorf("load config: %w; %w", io.EOF, ErrTimeout),
+			result: "load config: EOF; timeout\n" +
+				"├── EOF\n" +
+				"└── timeout\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.result, Tree(test.err, test.options...))
+		})
+	}
+}
+
+func loadConfig() error {
+	return stackerr.New("config not found")
+}
+
+func TestJSON(t *testing.T) {
+	data, err := JSON(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "null", string(data))
+
+	data, err = JSON(fmt.Errorf("start: %w", StorageErr.Wrap(io.EOF)))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"message": "start",
+		"causes": [{
+			"message": "storage is unavailable",
+			"code": "render.storage",
+			"causes": [{"message": "EOF"}]
+		}]
+	}`, string(data))
+
+	data, err = JSON(fmt.Errorf("load config: %w; %w", io.EOF, ErrTimeout))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"message": "load config: EOF; timeout",
+		"causes": [{"message": "EOF"}, {"message": "timeout"}]
+	}`, string(data))
+
+	data, err = JSON(multierror.Append(stackerr.Wrap(loadConfig(), "boot"), ErrTimeout, ErrTimeout), WithDedup())
+	require.NoError(t, err)
+
+	var node Node
+	require.NoError(t, json.Unmarshal(data, &node))
+	assert.Equal(t, "3 errors", node.Message)
+	require.Len(t, node.Causes, 2)
+
+	boot := node.Causes[0]
+	assert.Equal(t, "boot", boot.Message)
+	assert.Empty(t, boot.Stack)
+	require.Len(t, boot.Causes, 1)
+	require.NotEmpty(t, boot.Causes[0].Stack)
+	assert.True(t, strings.HasSuffix(boot.Causes[0].Stack[0].Function, "render.loadConfig"))
+	assert.Contains(t, boot.Causes[0].Stack[0].File, "render_test.go")
+
+	assert.Zero(t, boot.Count)
+	assert.Equal(t, "timeout", node.Causes[1].Message)
+	assert.Equal(t, 2, node.Causes[1].Count)
+}
+
+func TestList(t *testing.T) {
+	errs := []error{io.EOF, ErrTimeout, io.EOF, ErrTimeout, io.EOF, io.ErrClosedPipe, io.ErrShortWrite}
+
+	tests := map[string]struct {
+		options []Option
+		result  string
+	}{
+		"default layout": {
+			result: multierror.DefaultFormat(errs),
+		},
+		"deduplication": {
+			options: []Option{WithDedup()},
+			result: "7 errors occurred:\n" +
+				"\t* EOF (x3)\n" +
+				"\t* timeout (x2)\n" +
+				"\t* io: read/write on closed pipe\n" +
+				"\t* short write\n\n",
+		},
+		"truncation": {
+			options: []Option{WithDedup(), WithLimit(2)},
+			result: "7 errors occurred:\n" +
+				"\t* EOF (x3)\n" +
+				"\t* timeout (x2)\n" +
+				"\t* ... and 2 more errors\n\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := multierror.Append(nil, errs...).WithFormat(List(test.options...))
+			assert.EqualError(t, err, test.result)
+		})
+	}
+}
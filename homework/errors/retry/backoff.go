@@ -0,0 +1,42 @@
+package retry
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// Backoff returns the delay before the next attempt, attempt starts
+// from 1 and previous is the last delay (zero before the first one).
+type Backoff func(attempt int, previous time.Duration) time.Duration
+
+func Constant(delay time.Duration) Backoff {
+	return func(int, time.Duration) time.Duration {
+		return delay
+	}
+}
+
+// Exponential doubles the delay on every attempt up to limit.
+func Exponential(base, limit time.Duration) Backoff {
+	return func(attempt int, _ time.Duration) time.Duration {
+		delay := base
+		for range attempt - 1 {
+			if delay >= limit/2 {
+				return limit
+			}
+			delay *= 2
+		}
+		return min(delay, limit)
+	}
+}
+
+// DecorrelatedJitter picks a random delay between base and three times the
+// previous one, so clients that failed together do not retry together.
+func DecorrelatedJitter(base, limit time.Duration) Backoff {
+	return func(_ int, previous time.Duration) time.Duration {
+		upper := max(previous*3, base)
+		if upper <= base {
+			return min(base, limit)
+		}
+		return min(base+rand.N(upper-base), limit)
+	}
+}
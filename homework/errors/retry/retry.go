@@ -0,0 +1,191 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang_course/homework/errors/multierror"
+)
+
+// Classifier tells retryable errors from permanent ones. An error is retryable
+// when the first Retryable() or Temporary() method found in its chain says so,
+// or when it matches one of the registered sentinels.
+type Classifier struct {
+	mutex     sync.RWMutex
+	sentinels []error
+}
+
+var DefaultClassifier = &Classifier{}
+
+func (c *Classifier) Register(errs ...error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.sentinels = append(c.sentinels, errs...)
+}
+
+func (c *Classifier) IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var retryable interface{ Retryable() bool }
+	if errors.As(err, &retryable) {
+		return retryable.Retryable()
+	}
+
+	var temporary interface{ Temporary() bool }
+	if errors.As(err, &temporary) {
+		return temporary.Temporary()
+	}
+
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	for _, sentinel := range c.sentinels {
+		if errors.Is(err, sentinel) {
+			return true
+		}
+	}
+	return false
+}
+
+// Register adds sentinels to DefaultClassifier.
+func Register(errs ...error) {
+	DefaultClassifier.Register(errs...)
+}
+
+func IsRetryable(err error) bool {
+	return DefaultClassifier.IsRetryable(err)
+}
+
+type Clock interface {
+	Now() time.Time
+	After(delay time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) After(delay time.Duration) <-chan time.Time {
+	return time.After(delay)
+}
+
+// AttemptError keeps the number of the attempt that failed.
+type AttemptError struct {
+	Attempt int
+	Err     error
+}
+
+func (e *AttemptError) Error() string {
+	return fmt.Sprintf("attempt %d: %v", e.Attempt, e.Err)
+}
+
+func (e *AttemptError) Unwrap() error {
+	return e.Err
+}
+
+type Option func(*Retrier)
+
+// WithAttempts sets the total number of attempts including the first one.
+func WithAttempts(attempts int) Option {
+	return func(r *Retrier) {
+		if attempts > 0 {
+			r.attempts = attempts
+		}
+	}
+}
+
+func WithBackoff(backoff Backoff) Option {
+	return func(r *Retrier) {
+		r.backoff = backoff
+	}
+}
+
+func WithClock(clock Clock) Option {
+	return func(r *Retrier) {
+		r.clock = clock
+	}
+}
+
+func WithClassifier(classifier *Classifier) Option {
+	return func(r *Retrier) {
+		r.classifier = classifier
+	}
+}
+
+// WithMaxElapsed stops retrying once the next delay would end after the limit.
+func WithMaxElapsed(limit time.Duration) Option {
+	return func(r *Retrier) {
+		r.maxElapsed = limit
+	}
+}
+
+type Retrier struct {
+	attempts   int
+	backoff    Backoff
+	clock      Clock
+	classifier *Classifier
+	maxElapsed time.Duration
+}
+
+// New defaults to 3 attempts with exponential backoff from 100ms to 10s.
+func New(options ...Option) *Retrier {
+	retrier := &Retrier{
+		attempts:   3,
+		backoff:    Exponential(100*time.Millisecond, 10*time.Second),
+		clock:      realClock{},
+		classifier: DefaultClassifier,
+	}
+	for _, option := range options {
+		option(retrier)
+	}
+	return retrier
+}
+
+// Do calls fn until it succeeds, fails with a permanent error, runs out of
+// attempts or the context is done. On failure the result is a
+// *multierror.MultiError of every attempt error, followed by the context
+// cause when the context stopped the retries.
+func (r *Retrier) Do(ctx context.Context, fn func(context.Context) error) error {
+	var result *multierror.MultiError
+	start := r.clock.Now()
+
+	var delay time.Duration
+	for attempt := 1; ; attempt++ {
+		if err := context.Cause(ctx); err != nil {
+			return multierror.Append(result, err)
+		}
+
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+
+		result = multierror.Append(result, &AttemptError{Attempt: attempt, Err: err})
+		if attempt >= r.attempts || !r.classifier.IsRetryable(err) {
+			return result
+		}
+
+		delay = r.backoff(attempt, delay)
+		if r.maxElapsed > 0 && r.clock.Now().Add(delay).Sub(start) > r.maxElapsed {
+			return result
+		}
+
+		select {
+		case <-ctx.Done():
+			return multierror.Append(result, context.Cause(ctx))
+		case <-r.clock.After(delay):
+		}
+	}
+}
+
+func Do(ctx context.Context, fn func(context.Context) error, options ...Option) error {
+	return New(options...).Do(ctx, fn)
+}
@@ -0,0 +1,215 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang_course/homework/errors/multierror"
+)
+
+// go test -v .
+
+type fakeClock struct {
+	mutex  sync.Mutex
+	now    time.Time
+	sleeps []time.Duration
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(delay time.Duration) <-chan time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.now = c.now.Add(delay)
+	c.sleeps = append(c.sleeps, delay)
+
+	result := make(chan time.Time, 1)
+	result <- c.now
+	return result
+}
+
+type temporaryError struct {
+	temporary bool
+}
+
+func (e temporaryError) Error() string   { return "temporary error" }
+func (e temporaryError) Temporary() bool { return e.temporary }
+
+type retryableError struct{}
+
+func (retryableError) Error() string   { return "retryable error" }
+func (retryableError) Retryable() bool { return true }
+
+var ErrThrottled = errors.New("throttled")
+
+func failing(errs ...error) (func(context.Context) error, *int) {
+	calls := 0
+	return func(context.Context) error {
+		calls++
+		if calls > len(errs) {
+			return nil
+		}
+		return errs[calls-1]
+	}, &calls
+}
+
+func TestClassifier(t *testing.T) {
+	classifier := &Classifier{}
+	classifier.Register(ErrThrottled)
+
+	tests := map[string]struct {
+		err       error
+		retryable bool
+	}{
+		"nil":                {err: nil},
+		"plain":              {err: io.EOF},
+		"sentinel":           {err: ErrThrottled, retryable: true},
+		"wrapped sentinel":   {err: &AttemptError{Attempt: 1, Err: ErrThrottled}, retryable: true},
+		"temporary":          {err: temporaryError{temporary: true}, retryable: true},
+		"not temporary":      {err: temporaryError{temporary: false}},
+		"retryable":          {err: retryableError{}, retryable: true},
+		"canceled":           {err: context.Canceled},
+		"deadline":           {err: context.DeadlineExceeded},
+		"joined with plain":  {err: errors.Join(io.EOF, ErrThrottled), retryable: true},
+		"method wins":        {err: errors.Join(temporaryError{temporary: false}, ErrThrottled)},
+		"multierror wrapped": {err: multierror.Append(io.EOF, retryableError{}), retryable: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.retryable, classifier.IsRetryable(test.err))
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	exponential := Exponential(100*time.Millisecond, time.Second)
+	var delays []time.Duration
+	for attempt := 1; attempt <= 6; attempt++ {
+		delays = append(delays, exponential(attempt, 0))
+	}
+	assert.Equal(t, []time.Duration{
+		100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond,
+		800 * time.Millisecond, time.Second, time.Second,
+	}, delays)
+	assert.Equal(t, time.Second, exponential(1000, 0))
+
+	assert.Equal(t, time.Second, Constant(time.Second)(10, time.Minute))
+
+	jitter := DecorrelatedJitter(100*time.Millisecond, time.Second)
+	var previous time.Duration
+	for attempt := 1; attempt <= 100; attempt++ {
+		delay := jitter(attempt, previous)
+		assert.GreaterOrEqual(t, delay, 100*time.Millisecond)
+		assert.LessOrEqual(t, delay, max(previous*3, 100*time.Millisecond))
+		assert.LessOrEqual(t, delay, time.Second)
+		previous = delay
+	}
+}
+
+func TestDo(t *testing.T) {
+	classifier := &Classifier{}
+	classifier.Register(ErrThrottled)
+
+	clock := &fakeClock{}
+	fn, calls := failing(ErrThrottled, temporaryError{temporary: true})
+	err := Do(context.Background(), fn,
+		WithClock(clock),
+		WithClassifier(classifier),
+		WithBackoff(Exponential(time.Second, time.Minute)),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, *calls)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, clock.sleeps)
+}
+
+func TestGiveUp(t *testing.T) {
+	classifier := &Classifier{}
+	classifier.Register(ErrThrottled)
+
+	tests := map[string]struct {
+		errs     []error
+		options  []Option
+		calls    int
+		attempts []int
+	}{
+		"attempts exhausted": {
+			errs:     []error{ErrThrottled, ErrThrottled, ErrThrottled, ErrThrottled},
+			options:  []Option{WithAttempts(3)},
+			calls:    3,
+			attempts: []int{1, 2, 3},
+		},
+		"permanent error": {
+			errs:     []error{ErrThrottled, io.EOF, ErrThrottled},
+			options:  []Option{WithAttempts(5)},
+			calls:    2,
+			attempts: []int{1, 2},
+		},
+		"max elapsed": {
+			errs:     []error{ErrThrottled, ErrThrottled, ErrThrottled, ErrThrottled},
+			options:  []Option{WithAttempts(10), WithMaxElapsed(3 * time.Second)},
+			calls:    2,
+			attempts: []int{1, 2},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			fn, calls := failing(test.errs...)
+			options := append([]Option{
+				WithClock(&fakeClock{}),
+				WithClassifier(classifier),
+				WithBackoff(Constant(2 * time.Second)),
+			}, test.options...)
+
+			err := Do(context.Background(), fn, options...)
+			assert.Equal(t, test.calls, *calls)
+
+			var multiErr *multierror.MultiError
+			require.ErrorAs(t, err, &multiErr)
+
+			var attempts []int
+			for _, err := range multiErr.Errors() {
+				var attemptErr *AttemptError
+				require.ErrorAs(t, err, &attemptErr)
+				attempts = append(attempts, attemptErr.Attempt)
+			}
+			assert.Equal(t, test.attempts, attempts)
+		})
+	}
+}
+
+func TestContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	fn, calls := failing(retryableError{})
+	err := Do(ctx, fn, WithClock(&fakeClock{}))
+	assert.Equal(t, 0, *calls)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// a real clock with a long delay, the context deadline interrupts the sleep
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	fn, calls = failing(retryableError{}, retryableError{})
+	start := time.Now()
+	err = Do(ctx, fn, WithBackoff(Constant(time.Hour)))
+	assert.Less(t, time.Since(start), time.Minute)
+	assert.Equal(t, 1, *calls)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorAs(t, err, new(retryableError))
+	assert.Equal(t, "attempt 1: retryable error", err.(*multierror.MultiError).Errors()[0].Error())
+}
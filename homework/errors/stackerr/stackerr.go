@@ -0,0 +1,160 @@
+package stackerr
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+const maxDepth = 32
+
+type Frame struct {
+	Function string
+	File     string
+	Line     int
+}
+
+type StackTrace []Frame
+
+func (s StackTrace) String() string {
+	var builder strings.Builder
+	for idx, frame := range s {
+		if idx != 0 {
+			builder.WriteByte('\n')
+		}
+		builder.WriteString(frame.Function + "\n\t" + frame.File + ":" + strconv.Itoa(frame.Line))
+	}
+	return builder.String()
+}
+
+// StackTracer is implemented by every error of this package,
+// use errors.As to get the trace from a wrapped error.
+type StackTracer interface {
+	StackTrace() StackTrace
+}
+
+// stackError captures program counters only when no error
+// down the chain has done it already.
+type stackError struct {
+	message string
+	cause   error
+	stack   []uintptr
+}
+
+func New(message string) error {
+	return &stackError{message: message, stack: callers()}
+}
+
+// Errorf supports %w, the stack is reused from a wrapped error if it has one.
+func Errorf(format string, args ...any) error {
+	err := fmt.Errorf(format, args...)
+	if !isWrapper(err) {
+		return &stackError{message: err.Error(), stack: callers()}
+	}
+
+	result := &stackError{cause: err}
+	if !hasStack(err) {
+		result.stack = callers()
+	}
+	return result
+}
+
+// Wrap returns nil for a nil error.
+func Wrap(err error, message string) error {
+	if err == nil {
+		return nil
+	}
+
+	result := &stackError{message: message, cause: err}
+	if !hasStack(err) {
+		result.stack = callers()
+	}
+	return result
+}
+
+func Wrapf(err error, format string, args ...any) error {
+	if err == nil {
+		return nil
+	}
+
+	result := &stackError{message: fmt.Sprintf(format, args...), cause: err}
+	if !hasStack(err) {
+		result.stack = callers()
+	}
+	return result
+}
+
+func (e *stackError) Error() string {
+	switch {
+	case e.cause == nil:
+		return e.message
+	case e.message == "":
+		return e.cause.Error()
+	}
+	return e.message + ": " + e.cause.Error()
+}
+
+func (e *stackError) Unwrap() error {
+	return e.cause
+}
+
+// StackTrace returns the trace captured at the deepest point of the chain.
+func (e *stackError) StackTrace() StackTrace {
+	if e.stack == nil {
+		var tracer StackTracer
+		if errors.As(e.cause, &tracer) {
+			return tracer.StackTrace()
+		}
+		return nil
+	}
+
+	trace := make(StackTrace, 0, len(e.stack))
+	frames := runtime.CallersFrames(e.stack)
+	for {
+		frame, more := frames.Next()
+		trace = append(trace, Frame{Function: frame.Function, File: frame.File, Line: frame.Line})
+		if !more {
+			return trace
+		}
+	}
+}
+
+// Format prints the message for %s and %v and appends the stack trace for %+v.
+func (e *stackError) Format(state fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		io.WriteString(state, e.Error())
+		if state.Flag('+') {
+			if trace := e.StackTrace(); len(trace) != 0 {
+				io.WriteString(state, "\n"+trace.String())
+			}
+		}
+	case 's':
+		io.WriteString(state, e.Error())
+	case 'q':
+		fmt.Fprintf(state, "%q", e.Error())
+	}
+}
+
+func callers() []uintptr {
+	var pcs [maxDepth]uintptr
+	// skip runtime.Callers, callers and the exported constructor
+	count := runtime.Callers(3, pcs[:])
+	return pcs[:count]
+}
+
+func hasStack(err error) bool {
+	var tracer StackTracer
+	return errors.As(err, &tracer)
+}
+
+func isWrapper(err error) bool {
+	switch err.(type) {
+	case interface{ Unwrap() error }, interface{ Unwrap() []error }:
+		return true
+	}
+	return false
+}
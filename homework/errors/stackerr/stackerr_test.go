@@ -0,0 +1,100 @@
+package stackerr
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang_course/homework/errors/multierror"
+)
+
+// go test -v .
+
+func readConfig() error {
+	return New("config not found")
+}
+
+func loadService() error {
+	return Wrap(readConfig(), "load service")
+}
+
+func topFunction(t *testing.T, err error) string {
+	t.Helper()
+	var tracer StackTracer
+	require.True(t, errors.As(err, &tracer))
+	trace := tracer.StackTrace()
+	require.NotEmpty(t, trace)
+	return trace[0].Function
+}
+
+func TestNew(t *testing.T) {
+	err := readConfig()
+	assert.EqualError(t, err, "config not found")
+	assert.True(t, strings.HasSuffix(topFunction(t, err), "stackerr.readConfig"))
+}
+
+func TestWrapKeepsDeepestStack(t *testing.T) {
+	err := loadService()
+	assert.EqualError(t, err, "load service: config not found")
+	assert.True(t, strings.HasSuffix(topFunction(t, err), "stackerr.readConfig"))
+
+	assert.Nil(t, Wrap(nil, "ignored"))
+	assert.Nil(t, Wrapf(nil, "ignored %d", 1))
+}
+
+func TestWrapPlainError(t *testing.T) {
+	err := Wrapf(io.EOF, "read %s", "header")
+	assert.EqualError(t, err, "read header: EOF")
+	assert.ErrorIs(t, err, io.EOF)
+	assert.True(t, strings.HasSuffix(topFunction(t, err), "stackerr.TestWrapPlainError"))
+}
+
+func TestErrorf(t *testing.T) {
+	plain := Errorf("user %d not found", 7)
+	assert.EqualError(t, plain, "user 7 not found")
+	assert.Nil(t, errors.Unwrap(plain))
+	assert.True(t, strings.HasSuffix(topFunction(t, plain), "stackerr.TestErrorf"))
+
+	wrapped := Errorf("request failed: %w", readConfig())
+	assert.EqualError(t, wrapped, "request failed: config not found")
+	assert.True(t, strings.HasSuffix(topFunction(t, wrapped), "stackerr.readConfig"))
+
+	standard := Wrap(fmt.Errorf("std: %w", loadService()), "outer")
+	assert.EqualError(t, standard, "outer: std: load service: config not found")
+	assert.True(t, strings.HasSuffix(topFunction(t, standard), "stackerr.readConfig"))
+}
+
+func TestFormat(t *testing.T) {
+	err := loadService()
+	assert.Equal(t, "load service: config not found", fmt.Sprintf("%v", err))
+	assert.Equal(t, "load service: config not found", fmt.Sprintf("%s", err))
+	assert.Equal(t, `"load service: config not found"`, fmt.Sprintf("%q", err))
+
+	verbose := fmt.Sprintf("%+v", err)
+	lines := strings.Split(verbose, "\n")
+	require.Greater(t, len(lines), 3)
+	assert.Equal(t, "load service: config not found", lines[0])
+	assert.True(t, strings.HasSuffix(lines[1], "stackerr.readConfig"))
+	assert.Contains(t, lines[2], "stackerr_test.go:")
+	assert.Contains(t, verbose, "stackerr.loadService")
+}
+
+func TestMultiError(t *testing.T) {
+	err := multierror.Append(io.EOF, loadService())
+
+	var tracer StackTracer
+	require.True(t, errors.As(err, &tracer))
+	assert.True(t, strings.HasSuffix(tracer.StackTrace()[0].Function, "stackerr.readConfig"))
+
+	verbose := fmt.Sprintf("%+v", err)
+	assert.Contains(t, verbose, "\t* EOF\n\t* load service: config not found\n\t  ")
+	assert.Contains(t, verbose, "stackerr.readConfig")
+
+	wrapped := Wrap(err, "batch")
+	assert.True(t, strings.HasSuffix(topFunction(t, wrapped), "stackerr.readConfig"))
+}
@@ -0,0 +1,247 @@
+package conveyor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang_course/homework/errors/panics"
+)
+
+// StageError is returned by Wait when a stage fails in fail-fast mode.
+type StageError struct {
+	Stage string
+	Item  any
+	Err   error
+}
+
+func (e *StageError) Error() string {
+	return fmt.Sprintf("stage %q: %v", e.Stage, e.Err)
+}
+
+func (e *StageError) Unwrap() error {
+	return e.Err
+}
+
+// DeadLetter is an item a stage failed to process.
+type DeadLetter = StageError
+
+type Option func(*Pipeline)
+
+// WithDeadLetter switches the pipeline from fail-fast to dead-letter mode:
+// failed items are passed to handler and the rest keep flowing.
+// Calls of handler are serialized.
+func WithDeadLetter(handler func(*DeadLetter)) Option {
+	return func(p *Pipeline) {
+		p.deadLetter = handler
+	}
+}
+
+// Pipeline owns the goroutines of the connected stages. Stages are linked
+// with Connect, the last output has to be drained, for example by Collect.
+type Pipeline struct {
+	parent     context.Context
+	ctx        context.Context
+	cancel     context.CancelCauseFunc
+	deadLetter func(*DeadLetter)
+
+	wg              sync.WaitGroup
+	deadLetterMutex sync.Mutex
+	mutex           sync.Mutex
+	err             error
+	metrics         []*stageMetrics
+}
+
+func New(ctx context.Context, opts ...Option) *Pipeline {
+	pipeline := &Pipeline{parent: ctx}
+	pipeline.ctx, pipeline.cancel = context.WithCancelCause(ctx)
+	for _, opt := range opts {
+		opt(pipeline)
+	}
+	return pipeline
+}
+
+// Context is canceled on the first failure in fail-fast mode.
+func (p *Pipeline) Context() context.Context {
+	return p.ctx
+}
+
+// Wait blocks until every stage has stopped. It returns the *StageError in
+// fail-fast mode or the cause when the parent context was canceled.
+func (p *Pipeline) Wait() error {
+	p.wg.Wait()
+	p.cancel(context.Canceled)
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.err != nil {
+		return p.err
+	}
+	return context.Cause(p.parent)
+}
+
+// Metrics returns a snapshot for every stage in the order they were connected.
+func (p *Pipeline) Metrics() []Metrics {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	result := make([]Metrics, 0, len(p.metrics))
+	for _, metrics := range p.metrics {
+		result = append(result, metrics.snapshot())
+	}
+	return result
+}
+
+func (p *Pipeline) fail(failure *StageError) {
+	if p.deadLetter != nil {
+		p.deadLetterMutex.Lock()
+		defer p.deadLetterMutex.Unlock()
+		p.deadLetter(failure)
+		return
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.err == nil {
+		p.err = failure
+		p.cancel(failure)
+	}
+}
+
+func (p *Pipeline) register(stage string) *stageMetrics {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	metrics := &stageMetrics{stage: stage, started: time.Now()}
+	p.metrics = append(p.metrics, metrics)
+	return metrics
+}
+
+type StageOption func(*stageConfig)
+
+type stageConfig struct {
+	workers int
+	buffer  int
+}
+
+// WithWorkers sets how many items the stage processes at once,
+// with more than one worker the output order is not kept.
+func WithWorkers(workers int) StageOption {
+	return func(c *stageConfig) {
+		if workers > 0 {
+			c.workers = workers
+		}
+	}
+}
+
+// WithBuffer sets the capacity of the output channel. A full buffer blocks
+// the stage, so a slow consumer holds back the whole conveyor.
+func WithBuffer(size int) StageOption {
+	return func(c *stageConfig) {
+		c.buffer = max(size, 0)
+	}
+}
+
+type Stage[In, Out any] struct {
+	name   string
+	fn     func(context.Context, In) (Out, error)
+	config stageConfig
+}
+
+func NewStage[In, Out any](name string, fn func(context.Context, In) (Out, error), opts ...StageOption) *Stage[In, Out] {
+	stage := &Stage[In, Out]{name: name, fn: fn, config: stageConfig{workers: 1}}
+	for _, opt := range opts {
+		opt(&stage.config)
+	}
+	return stage
+}
+
+// Source feeds values into the pipeline.
+func Source[T any](p *Pipeline, values []T) <-chan T {
+	output := make(chan T)
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		defer close(output)
+		for _, value := range values {
+			select {
+			case output <- value:
+			case <-p.ctx.Done():
+				return
+			}
+		}
+	}()
+	return output
+}
+
+// Connect starts the stage workers reading from input and returns the stage output.
+// The output is closed when input is exhausted or the pipeline is stopped.
+func Connect[In, Out any](p *Pipeline, input <-chan In, stage *Stage[In, Out]) <-chan Out {
+	output := make(chan Out, stage.config.buffer)
+	metrics := p.register(stage.name)
+
+	var workers sync.WaitGroup
+	for range stage.config.workers {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			stage.work(p, input, output, metrics)
+		}()
+	}
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		workers.Wait()
+		metrics.finish()
+		close(output)
+	}()
+	return output
+}
+
+func (s *Stage[In, Out]) work(p *Pipeline, input <-chan In, output chan<- Out, metrics *stageMetrics) {
+	for {
+		var item In
+		var ok bool
+		select {
+		case item, ok = <-input:
+			if !ok {
+				return
+			}
+		case <-p.ctx.Done():
+			return
+		}
+
+		start := time.Now()
+		var result Out
+		err := panics.Catch(func() error {
+			var err error
+			result, err = s.fn(p.ctx, item)
+			return err
+		})
+		metrics.observe(time.Since(start), err)
+
+		if err != nil {
+			p.fail(&StageError{Stage: s.name, Item: item, Err: err})
+			continue
+		}
+
+		select {
+		case output <- result:
+		case <-p.ctx.Done():
+			return
+		}
+	}
+}
+
+// Collect drains the last output and waits for the pipeline.
+func Collect[T any](p *Pipeline, output <-chan T) ([]T, error) {
+	var result []T
+	for value := range output {
+		result = append(result, value)
+	}
+	return result, p.Wait()
+}
@@ -0,0 +1,218 @@
+package conveyor
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang_course/homework/errors/panics"
+)
+
+// go test -v -race .
+
+func numbers(count int) []int {
+	result := make([]int, count)
+	for idx := range result {
+		result[idx] = idx + 1
+	}
+	return result
+}
+
+func sqr(_ context.Context, number int) (int, error) {
+	return number * number, nil
+}
+
+func neg(_ context.Context, number int) (int, error) {
+	return -number, nil
+}
+
+func format(_ context.Context, number int) (string, error) {
+	return strconv.Itoa(number), nil
+}
+
+var errOdd = errors.New("odd number")
+
+func evenOnly(_ context.Context, number int) (int, error) {
+	if number%2 != 0 {
+		return 0, errOdd
+	}
+	return number, nil
+}
+
+func TestTypedStages(t *testing.T) {
+	pipeline := New(context.Background())
+
+	squares := Connect(pipeline, Source(pipeline, []int{1, 2, 3, 4, 5}), NewStage("sqr", sqr))
+	negatives := Connect(pipeline, squares, NewStage("neg", neg))
+	texts := Connect(pipeline, negatives, NewStage("format", format))
+
+	result, err := Collect(pipeline, texts)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"-1", "-4", "-9", "-16", "-25"}, result)
+
+	var stages []string
+	for _, metrics := range pipeline.Metrics() {
+		stages = append(stages, metrics.Stage)
+		assert.Equal(t, uint64(5), metrics.Processed)
+		assert.Zero(t, metrics.Failed)
+	}
+	assert.Equal(t, []string{"sqr", "neg", "format"}, stages)
+}
+
+func TestParallelStage(t *testing.T) {
+	pipeline := New(context.Background())
+
+	var running, peak atomic.Int64
+	slow := NewStage("slow", func(_ context.Context, number int) (int, error) {
+		current := running.Add(1)
+		defer running.Add(-1)
+		for {
+			observed := peak.Load()
+			if current <= observed || peak.CompareAndSwap(observed, current) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		return number * 2, nil
+	}, WithWorkers(4), WithBuffer(10))
+
+	result, err := Collect(pipeline, Connect(pipeline, Source(pipeline, numbers(40)), slow))
+	require.NoError(t, err)
+
+	expected := make([]int, 40)
+	for idx := range expected {
+		expected[idx] = 2 * (idx + 1)
+	}
+	assert.ElementsMatch(t, expected, result)
+	assert.Equal(t, int64(4), peak.Load())
+
+	metrics := pipeline.Metrics()[0]
+	assert.Equal(t, uint64(40), metrics.Processed)
+	assert.GreaterOrEqual(t, metrics.AvgLatency, 5*time.Millisecond)
+	assert.GreaterOrEqual(t, metrics.MaxLatency, metrics.AvgLatency)
+	assert.Greater(t, metrics.Throughput, 0.0)
+	assert.Less(t, metrics.Elapsed, 40*5*time.Millisecond)
+}
+
+func TestBackpressure(t *testing.T) {
+	pipeline := New(context.Background())
+
+	var processed atomic.Int64
+	stage := NewStage("count", func(_ context.Context, number int) (int, error) {
+		processed.Add(1)
+		return number, nil
+	}, WithBuffer(2))
+	output := Connect(pipeline, Source(pipeline, numbers(100)), stage)
+
+	for range 3 {
+		<-output
+	}
+	// three values read, two in the buffer and one waiting to be sent
+	require.Eventually(t, func() bool { return processed.Load() == 6 }, time.Second, time.Millisecond)
+	assert.Never(t, func() bool { return processed.Load() > 6 }, 20*time.Millisecond, time.Millisecond)
+
+	result, err := Collect(pipeline, output)
+	require.NoError(t, err)
+	assert.Len(t, result, 97)
+}
+
+func TestFailFast(t *testing.T) {
+	pipeline := New(context.Background())
+
+	var processed atomic.Int64
+	stage := NewStage("even", func(ctx context.Context, number int) (int, error) {
+		processed.Add(1)
+		return evenOnly(ctx, number)
+	})
+
+	source := Source(pipeline, []int{2, 4, 5, 6, 8, 10, 12, 14})
+	_, err := Collect(pipeline, Connect(pipeline, source, stage))
+
+	var stageErr *StageError
+	require.ErrorAs(t, err, &stageErr)
+	assert.Equal(t, "even", stageErr.Stage)
+	assert.Equal(t, 5, stageErr.Item)
+	assert.ErrorIs(t, err, errOdd)
+	assert.EqualError(t, err, `stage "even": odd number`)
+	assert.ErrorIs(t, context.Cause(pipeline.Context()), errOdd)
+	assert.Less(t, processed.Load(), int64(8))
+}
+
+func TestDeadLetter(t *testing.T) {
+	var letters []*DeadLetter
+	pipeline := New(context.Background(), WithDeadLetter(func(letter *DeadLetter) {
+		letters = append(letters, letter)
+	}))
+
+	even := Connect(pipeline, Source(pipeline, numbers(10)), NewStage("even", evenOnly, WithWorkers(3)))
+	panicking := NewStage("no sixes", func(_ context.Context, number int) (int, error) {
+		if number == 6 {
+			panic("six")
+		}
+		return number, nil
+	})
+
+	result, err := Collect(pipeline, Connect(pipeline, even, panicking))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []int{2, 4, 8, 10}, result)
+
+	require.Len(t, letters, 6)
+	var odd []any
+	for _, letter := range letters {
+		if letter.Stage == "even" {
+			assert.ErrorIs(t, letter, errOdd)
+			odd = append(odd, letter.Item)
+			continue
+		}
+
+		assert.Equal(t, "no sixes", letter.Stage)
+		assert.Equal(t, 6, letter.Item)
+		var panicErr *panics.PanicError
+		assert.ErrorAs(t, letter, &panicErr)
+	}
+	assert.ElementsMatch(t, []any{1, 3, 5, 7, 9}, odd)
+
+	metrics := pipeline.Metrics()
+	assert.Equal(t, Metrics{Stage: "even", Processed: 10, Failed: 5}, Metrics{
+		Stage: metrics[0].Stage, Processed: metrics[0].Processed, Failed: metrics[0].Failed,
+	})
+	assert.Equal(t, uint64(1), metrics[1].Failed)
+}
+
+func TestShutdown(t *testing.T) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	pipeline := New(ctx)
+
+	errShutdown := errors.New("shutdown")
+	var processed atomic.Int64
+	stage := NewStage("slow", func(ctx context.Context, number int) (int, error) {
+		if processed.Add(1) == 10 {
+			cancel(errShutdown)
+		}
+		return number, nil
+	}, WithWorkers(2))
+
+	first := Connect(pipeline, Source(pipeline, numbers(1_000)), stage)
+	second := Connect(pipeline, first, NewStage("sqr", sqr))
+
+	done := make(chan struct{})
+	var err error
+	go func() {
+		defer close(done)
+		_, err = Collect(pipeline, second)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("pipeline did not stop")
+	}
+	assert.ErrorIs(t, err, errShutdown)
+	assert.Less(t, processed.Load(), int64(1_000))
+}
@@ -0,0 +1,72 @@
+package conveyor
+
+import (
+	"sync"
+	"time"
+)
+
+type Metrics struct {
+	Stage      string
+	Processed  uint64 // including failed items
+	Failed     uint64
+	Elapsed    time.Duration // since the stage was connected until it stopped
+	Throughput float64       // processed items per second of Elapsed
+	AvgLatency time.Duration
+	MaxLatency time.Duration
+}
+
+type stageMetrics struct {
+	stage string
+
+	mutex      sync.Mutex
+	started    time.Time
+	finished   time.Time
+	processed  uint64
+	failed     uint64
+	latency    time.Duration
+	maxLatency time.Duration
+}
+
+func (m *stageMetrics) observe(latency time.Duration, err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.processed++
+	if err != nil {
+		m.failed++
+	}
+	m.latency += latency
+	m.maxLatency = max(m.maxLatency, latency)
+}
+
+func (m *stageMetrics) finish() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.finished = time.Now()
+}
+
+func (m *stageMetrics) snapshot() Metrics {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	metrics := Metrics{
+		Stage:      m.stage,
+		Processed:  m.processed,
+		Failed:     m.failed,
+		MaxLatency: m.maxLatency,
+	}
+
+	if m.finished.IsZero() {
+		metrics.Elapsed = time.Since(m.started)
+	} else {
+		metrics.Elapsed = m.finished.Sub(m.started)
+	}
+	if m.processed != 0 {
+		metrics.AvgLatency = m.latency / time.Duration(m.processed)
+	}
+	if metrics.Elapsed > 0 {
+		metrics.Throughput = float64(m.processed) / metrics.Elapsed.Seconds()
+	}
+	return metrics
+}
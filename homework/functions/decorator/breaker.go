@@ -0,0 +1,176 @@
+package decorator
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrBreakerOpen = errors.New("circuit breaker is open")
+
+// errPanicked is recorded for calls that panic, the panic itself goes on.
+var errPanicked = errors.New("call panicked")
+
+type State int
+
+const (
+	StateClosed State = iota
+	StateOpen
+	StateHalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	}
+	return "closed"
+}
+
+type BreakerOption func(*Breaker)
+
+// WithFailureThreshold sets how many failures in a row open the breaker.
+func WithFailureThreshold(threshold int) BreakerOption {
+	return func(b *Breaker) {
+		b.threshold = threshold
+	}
+}
+
+// WithOpenTimeout sets how long the breaker stays open before a trial call.
+func WithOpenTimeout(timeout time.Duration) BreakerOption {
+	return func(b *Breaker) {
+		b.openTimeout = timeout
+	}
+}
+
+// WithStateChange is called after every transition, outside the breaker lock.
+func WithStateChange(callback func(from, to State)) BreakerOption {
+	return func(b *Breaker) {
+		b.onChange = callback
+	}
+}
+
+func WithBreakerClock(now func() time.Time) BreakerOption {
+	return func(b *Breaker) {
+		b.now = now
+	}
+}
+
+// Breaker stops calling a failing function. After the open timeout a single
+// trial call is let through, its result closes or opens the breaker again.
+type Breaker struct {
+	threshold   int
+	openTimeout time.Duration
+	onChange    func(from, to State)
+	now         func() time.Time
+
+	mutex    sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+	trial    bool
+	// generation changes with every transition, results of calls
+	// started in an earlier state are ignored
+	generation uint64
+}
+
+// NewBreaker defaults to 5 failures and 30 seconds in the open state.
+func NewBreaker(options ...BreakerOption) *Breaker {
+	breaker := &Breaker{threshold: 5, openTimeout: 30 * time.Second, now: time.Now}
+	for _, option := range options {
+		option(breaker)
+	}
+	return breaker
+}
+
+func (b *Breaker) State() State {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.state
+}
+
+// allow returns the generation the call belongs to, it is passed to record.
+func (b *Breaker) allow() (uint64, error) {
+	b.mutex.Lock()
+	from := b.state
+	if b.state == StateOpen && !b.now().Before(b.openedAt.Add(b.openTimeout)) {
+		b.transition(StateHalfOpen)
+		b.trial = false
+	}
+
+	var err error
+	switch {
+	case b.state == StateOpen, b.state == StateHalfOpen && b.trial:
+		err = ErrBreakerOpen
+	case b.state == StateHalfOpen:
+		b.trial = true
+	}
+	to, generation := b.state, b.generation
+	b.mutex.Unlock()
+
+	b.notify(from, to)
+	return generation, err
+}
+
+func (b *Breaker) record(generation uint64, err error) {
+	b.mutex.Lock()
+	from := b.state
+	// only the trial call decides how the breaker leaves half-open, and calls
+	// started before the breaker opened do not change the open state
+	switch {
+	case generation != b.generation:
+	case err == nil:
+		b.failures = 0
+		b.transition(StateClosed)
+	case b.state == StateHalfOpen:
+		b.openedAt = b.now()
+		b.transition(StateOpen)
+	default:
+		b.failures++
+		if b.failures >= b.threshold {
+			b.openedAt, b.failures = b.now(), 0
+			b.transition(StateOpen)
+		}
+	}
+	to := b.state
+	b.mutex.Unlock()
+
+	b.notify(from, to)
+}
+
+func (b *Breaker) transition(to State) {
+	if b.state != to {
+		b.state = to
+		b.generation++
+	}
+}
+
+func (b *Breaker) notify(from, to State) {
+	if from != to && b.onChange != nil {
+		b.onChange(from, to)
+	}
+}
+
+// CircuitBreaker guards calls with breaker, it can be shared by several functions.
+func CircuitBreaker[A, B any](breaker *Breaker) Decorator[A, B] {
+	return func(fn Func[A, B]) Func[A, B] {
+		return func(ctx context.Context, arg A) (result B, err error) {
+			generation, err := breaker.allow()
+			if err != nil {
+				return result, err
+			}
+
+			// stays set when fn panics, so the trial call always finishes half-open
+			err = errPanicked
+			defer func() {
+				breaker.record(generation, err)
+			}()
+
+			result, err = fn(ctx, arg)
+			return result, err
+		}
+	}
+}
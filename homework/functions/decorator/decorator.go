@@ -0,0 +1,189 @@
+package decorator
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"golang_course/homework/errors/panics"
+	"golang_course/homework/errors/retry"
+)
+
+type Func[A, B any] func(context.Context, A) (B, error)
+
+type Decorator[A, B any] func(Func[A, B]) Func[A, B]
+
+// Chain wraps fn in the declared order, the first decorator is the outermost:
+// Chain(fn, Logging, Retry) logs once around all the retries.
+func Chain[A, B any](fn Func[A, B], decorators ...Decorator[A, B]) Func[A, B] {
+	for idx := len(decorators) - 1; idx >= 0; idx-- {
+		fn = decorators[idx](fn)
+	}
+	return fn
+}
+
+// Logging writes a record per call, failures are logged with the error level.
+func Logging[A, B any](logger *slog.Logger, name string) Decorator[A, B] {
+	return func(fn Func[A, B]) Func[A, B] {
+		return func(ctx context.Context, arg A) (B, error) {
+			start := time.Now()
+			result, err := fn(ctx, arg)
+
+			attrs := []slog.Attr{
+				slog.String("func", name),
+				slog.Any("arg", arg),
+				slog.Duration("duration", time.Since(start)),
+			}
+			if err != nil {
+				logger.LogAttrs(ctx, slog.LevelError, "call failed", append(attrs, slog.Any("error", err))...)
+			} else {
+				logger.LogAttrs(ctx, slog.LevelInfo, "call", append(attrs, slog.Any("result", result))...)
+			}
+			return result, err
+		}
+	}
+}
+
+// Timing reports the duration of every call.
+func Timing[A, B any](observe func(duration time.Duration, err error)) Decorator[A, B] {
+	return func(fn Func[A, B]) Func[A, B] {
+		return func(ctx context.Context, arg A) (B, error) {
+			start := time.Now()
+			result, err := fn(ctx, arg)
+			observe(time.Since(start), err)
+			return result, err
+		}
+	}
+}
+
+// Timeout returns context.DeadlineExceeded once the timeout expires even if
+// fn ignores the context, in that case fn keeps running in the background.
+// A panic in fn is raised again in the caller as *panics.PanicError with the
+// stack of fn, after the timeout it is dropped.
+func Timeout[A, B any](timeout time.Duration) Decorator[A, B] {
+	type outcome struct {
+		result B
+		err    error
+		panic  error
+	}
+
+	return func(fn Func[A, B]) Func[A, B] {
+		return func(ctx context.Context, arg A) (B, error) {
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			done := make(chan outcome, 1)
+			go func() {
+				var outcome outcome
+				defer func() { done <- outcome }()
+				defer panics.Recover(&outcome.panic)
+				outcome.result, outcome.err = fn(ctx, arg)
+			}()
+
+			select {
+			case outcome := <-done:
+				if outcome.panic != nil {
+					panic(outcome.panic)
+				}
+				return outcome.result, outcome.err
+			case <-ctx.Done():
+				var zero B
+				return zero, context.Cause(ctx)
+			}
+		}
+	}
+}
+
+// Retry repeats failed calls with the retry package, see retry.Retrier.Do.
+func Retry[A, B any](options ...retry.Option) Decorator[A, B] {
+	retrier := retry.New(options...)
+	return func(fn Func[A, B]) Func[A, B] {
+		return func(ctx context.Context, arg A) (B, error) {
+			var result B
+			err := retrier.Do(ctx, func(ctx context.Context) error {
+				var err error
+				result, err = fn(ctx, arg)
+				return err
+			})
+			return result, err
+		}
+	}
+}
+
+// ConcurrencyLimit lets at most limit calls run at the same time,
+// the others wait for a slot or for the context.
+func ConcurrencyLimit[A, B any](limit int) Decorator[A, B] {
+	semaphore := make(chan struct{}, limit)
+	return func(fn Func[A, B]) Func[A, B] {
+		return func(ctx context.Context, arg A) (B, error) {
+			select {
+			case semaphore <- struct{}{}:
+			case <-ctx.Done():
+				var zero B
+				return zero, context.Cause(ctx)
+			}
+			defer func() { <-semaphore }()
+
+			return fn(ctx, arg)
+		}
+	}
+}
+
+// RateLimit allows rate calls per second on average with bursts up to burst calls.
+func RateLimit[A, B any](rate float64, burst int) Decorator[A, B] {
+	bucket := &tokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+	return func(fn Func[A, B]) Func[A, B] {
+		return func(ctx context.Context, arg A) (B, error) {
+			if err := bucket.wait(ctx); err != nil {
+				var zero B
+				return zero, err
+			}
+			return fn(ctx, arg)
+		}
+	}
+}
+
+type tokenBucket struct {
+	mutex  sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// reserve takes a token, tokens can go below zero to queue the callers.
+func (b *tokenBucket) reserve() time.Duration {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	now := time.Now()
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+func (b *tokenBucket) wait(ctx context.Context) error {
+	delay := b.reserve()
+	if delay == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		b.mutex.Lock()
+		b.tokens++
+		b.mutex.Unlock()
+		return context.Cause(ctx)
+	}
+}
@@ -0,0 +1,412 @@
+package decorator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang_course/homework/errors/panics"
+	"golang_course/homework/errors/retry"
+)
+
+// go test -v -race .
+
+type Pair struct {
+	X, Y int
+}
+
+func Add(_ context.Context, pair Pair) (int, error) {
+	return pair.X + pair.Y, nil
+}
+
+func newLogger(buffer *bytes.Buffer) *slog.Logger {
+	return slog.New(slog.NewTextHandler(buffer, &slog.HandlerOptions{
+		ReplaceAttr: func(_ []string, attr slog.Attr) slog.Attr {
+			if attr.Key == slog.TimeKey || attr.Key == "duration" {
+				return slog.Attr{}
+			}
+			return attr
+		},
+	}))
+}
+
+func TestLogging(t *testing.T) {
+	var buffer bytes.Buffer
+	calculateAdd := Chain(Add, Logging[Pair, int](newLogger(&buffer), "add"))
+
+	result, err := calculateAdd(context.Background(), Pair{X: 10, Y: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 20, result)
+	assert.Equal(t, "level=INFO msg=call func=add arg=\"{X:10 Y:10}\" result=20\n", buffer.String())
+
+	buffer.Reset()
+	failing := Chain(func(context.Context, string) (int, error) {
+		return 0, io.EOF
+	}, Logging[string, int](newLogger(&buffer), "read"))
+
+	_, err = failing(context.Background(), "file")
+	assert.ErrorIs(t, err, io.EOF)
+	assert.Equal(t, "level=ERROR msg=\"call failed\" func=read arg=file error=EOF\n", buffer.String())
+}
+
+func TestChainOrder(t *testing.T) {
+	var trace []string
+	named := func(name string) Decorator[int, int] {
+		return func(fn Func[int, int]) Func[int, int] {
+			return func(ctx context.Context, arg int) (int, error) {
+				trace = append(trace, name+" before")
+				result, err := fn(ctx, arg)
+				trace = append(trace, name+" after")
+				return result, err
+			}
+		}
+	}
+
+	fn := Chain(func(_ context.Context, arg int) (int, error) {
+		trace = append(trace, "call")
+		return arg, nil
+	}, named("first"), named("second"))
+
+	_, err := fn(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"first before", "second before", "call", "second after", "first after"}, trace)
+}
+
+func TestTiming(t *testing.T) {
+	var observed time.Duration
+	fn := Chain(func(context.Context, int) (int, error) {
+		time.Sleep(5 * time.Millisecond)
+		return 0, nil
+	}, Timing[int, int](func(duration time.Duration, err error) {
+		observed = duration
+	}))
+
+	_, _ = fn(context.Background(), 0)
+	assert.GreaterOrEqual(t, observed, 5*time.Millisecond)
+}
+
+func TestTimeout(t *testing.T) {
+	fn := Chain(func(ctx context.Context, delay time.Duration) (string, error) {
+		time.Sleep(delay)
+		return "done", nil
+	}, Timeout[time.Duration, string](20*time.Millisecond))
+
+	result, err := fn(context.Background(), 0)
+	require.NoError(t, err)
+	assert.Equal(t, "done", result)
+
+	start := time.Now()
+	_, err = fn(context.Background(), time.Second)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+
+	// the panic reaches the caller instead of crashing the program
+	panicking := Chain(func(context.Context, int) (int, error) {
+		panic(io.ErrUnexpectedEOF)
+	}, Timeout[int, int](time.Second))
+
+	err = panics.Catch(func() error {
+		_, err := panicking(context.Background(), 0)
+		return err
+	})
+	var panicErr *panics.PanicError
+	require.ErrorAs(t, err, &panicErr)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	assert.Contains(t, fmt.Sprintf("%+v", panicErr), "decorator.TestTimeout")
+}
+
+type temporaryError struct{}
+
+func (temporaryError) Error() string   { return "temporary" }
+func (temporaryError) Temporary() bool { return true }
+
+func TestRetryAndBreaker(t *testing.T) {
+	var transitions []string
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	breaker := NewBreaker(
+		WithFailureThreshold(3),
+		WithOpenTimeout(time.Minute),
+		WithBreakerClock(func() time.Time { return now }),
+		WithStateChange(func(from, to State) {
+			transitions = append(transitions, from.String()+" -> "+to.String())
+		}),
+	)
+
+	var calls int
+	healthy := false
+	fn := Chain(func(context.Context, int) (int, error) {
+		calls++
+		if !healthy {
+			return 0, temporaryError{}
+		}
+		return 42, nil
+	},
+		Retry[int, int](retry.WithAttempts(2), retry.WithBackoff(retry.Constant(0))),
+		CircuitBreaker[int, int](breaker),
+	)
+
+	// every attempt goes through the breaker, so the third failure opens it
+	_, err := fn(context.Background(), 0)
+	assert.ErrorAs(t, err, new(temporaryError))
+	_, err = fn(context.Background(), 0)
+	assert.ErrorIs(t, err, ErrBreakerOpen)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, StateOpen, breaker.State())
+
+	_, err = fn(context.Background(), 0)
+	assert.ErrorIs(t, err, ErrBreakerOpen)
+	assert.Equal(t, 3, calls)
+
+	// the trial call fails and the breaker opens again
+	now = now.Add(time.Minute)
+	_, err = fn(context.Background(), 0)
+	assert.Error(t, err)
+	assert.Equal(t, 4, calls)
+	assert.Equal(t, StateOpen, breaker.State())
+
+	now = now.Add(time.Minute)
+	healthy = true
+	result, err := fn(context.Background(), 0)
+	require.NoError(t, err)
+	assert.Equal(t, 42, result)
+	assert.Equal(t, StateClosed, breaker.State())
+
+	assert.Equal(t, []string{
+		"closed -> open",
+		"open -> half-open",
+		"half-open -> open",
+		"open -> half-open",
+		"half-open -> closed",
+	}, transitions)
+}
+
+func TestBreakerSingleTrial(t *testing.T) {
+	now := time.Now()
+	breaker := NewBreaker(WithFailureThreshold(1), WithOpenTimeout(time.Second), WithBreakerClock(func() time.Time { return now }))
+
+	release := make(chan struct{})
+	fn := Chain(func(_ context.Context, fail bool) (int, error) {
+		if fail {
+			return 0, io.EOF
+		}
+		<-release
+		return 1, nil
+	}, CircuitBreaker[bool, int](breaker))
+
+	_, _ = fn(context.Background(), true)
+	require.Equal(t, StateOpen, breaker.State())
+
+	now = now.Add(time.Second)
+	done := make(chan error)
+	go func() {
+		_, err := fn(context.Background(), false)
+		done <- err
+	}()
+
+	assert.Eventually(t, func() bool { return breaker.State() == StateHalfOpen }, time.Second, time.Millisecond)
+	_, err := fn(context.Background(), false)
+	assert.ErrorIs(t, err, ErrBreakerOpen)
+
+	close(release)
+	assert.NoError(t, <-done)
+	assert.Equal(t, StateClosed, breaker.State())
+}
+
+func TestBreakerIgnoresStaleCalls(t *testing.T) {
+	type call struct {
+		fail    bool
+		entered chan struct{}
+		release chan struct{}
+	}
+
+	now := time.Now()
+	var transitions []string
+	breaker := NewBreaker(
+		WithFailureThreshold(1),
+		WithOpenTimeout(time.Second),
+		WithBreakerClock(func() time.Time { return now }),
+		WithStateChange(func(from, to State) {
+			transitions = append(transitions, from.String()+" -> "+to.String())
+		}),
+	)
+	fn := Chain(func(_ context.Context, arg call) (int, error) {
+		if arg.entered != nil {
+			close(arg.entered)
+		}
+		if arg.release != nil {
+			<-arg.release
+		}
+		if arg.fail {
+			return 0, io.EOF
+		}
+		return 1, nil
+	}, CircuitBreaker[call, int](breaker))
+
+	start := func(arg call) chan error {
+		done := make(chan error, 1)
+		go func() {
+			_, err := fn(context.Background(), arg)
+			done <- err
+		}()
+		return done
+	}
+
+	// two slow calls start while the breaker is closed
+	slowSuccess := call{entered: make(chan struct{}), release: make(chan struct{})}
+	slowFailure := call{fail: true, entered: make(chan struct{}), release: make(chan struct{})}
+	successDone, failureDone := start(slowSuccess), start(slowFailure)
+	<-slowSuccess.entered
+	<-slowFailure.entered
+
+	_, err := fn(context.Background(), call{fail: true})
+	assert.ErrorIs(t, err, io.EOF)
+	require.Equal(t, StateOpen, breaker.State())
+
+	now = now.Add(time.Second)
+	trial := call{release: make(chan struct{})}
+	trialDone := start(trial)
+	require.Eventually(t, func() bool { return breaker.State() == StateHalfOpen }, time.Second, time.Millisecond)
+
+	// the results of the slow calls finish during the trial and are ignored
+	close(slowSuccess.release)
+	assert.NoError(t, <-successDone)
+	close(slowFailure.release)
+	assert.ErrorIs(t, <-failureDone, io.EOF)
+	assert.Equal(t, StateHalfOpen, breaker.State())
+
+	_, err = fn(context.Background(), call{})
+	assert.ErrorIs(t, err, ErrBreakerOpen)
+
+	close(trial.release)
+	assert.NoError(t, <-trialDone)
+	assert.Equal(t, StateClosed, breaker.State())
+	assert.Equal(t, []string{"closed -> open", "open -> half-open", "half-open -> closed"}, transitions)
+}
+
+func TestBreakerPanics(t *testing.T) {
+	now := time.Now()
+	breaker := NewBreaker(WithFailureThreshold(1), WithOpenTimeout(time.Second), WithBreakerClock(func() time.Time { return now }))
+	fn := Chain(func(_ context.Context, broken bool) (int, error) {
+		if broken {
+			panic("broken")
+		}
+		return 1, nil
+	}, CircuitBreaker[bool, int](breaker))
+
+	call := func(broken bool) error {
+		return panics.Catch(func() error {
+			_, err := fn(context.Background(), broken)
+			return err
+		})
+	}
+
+	// a panic is a failure in the closed state
+	var panicErr *panics.PanicError
+	require.ErrorAs(t, call(true), &panicErr)
+	assert.Equal(t, "broken", panicErr.Value)
+	require.Equal(t, StateOpen, breaker.State())
+
+	// and the panicking trial call opens the breaker again
+	now = now.Add(time.Second)
+	require.ErrorAs(t, call(true), &panicErr)
+	assert.Equal(t, StateOpen, breaker.State())
+
+	now = now.Add(time.Hour)
+	assert.NoError(t, call(false))
+	assert.Equal(t, StateClosed, breaker.State())
+}
+
+func TestConcurrencyLimit(t *testing.T) {
+	var running, peak atomic.Int64
+	fn := Chain(func(context.Context, int) (int, error) {
+		current := running.Add(1)
+		defer running.Add(-1)
+		for {
+			observed := peak.Load()
+			if current <= observed || peak.CompareAndSwap(observed, current) {
+				break
+			}
+		}
+		time.Sleep(2 * time.Millisecond)
+		return 0, nil
+	}, ConcurrencyLimit[int, int](3))
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = fn(context.Background(), 0)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int64(3), peak.Load())
+
+	blocked := Chain(func(ctx context.Context, _ int) (int, error) {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	}, ConcurrencyLimit[int, int](1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	go func() { _, _ = blocked(ctx, 0) }()
+	time.Sleep(time.Millisecond)
+	_, err := blocked(ctx, 0)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestRateLimit(t *testing.T) {
+	fn := Chain(Add, RateLimit[Pair, int](100, 2))
+
+	start := time.Now()
+	for range 6 {
+		_, err := fn(context.Background(), Pair{})
+		require.NoError(t, err)
+	}
+	// two calls from the burst and four more at 10ms each
+	assert.GreaterOrEqual(t, time.Since(start), 35*time.Millisecond)
+
+	slow := Chain(Add, RateLimit[Pair, int](1, 1))
+	_, _ = slow(context.Background(), Pair{})
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	errStop := errors.New("stop")
+	cancel(errStop)
+	_, err := slow(ctx, Pair{})
+	assert.ErrorIs(t, err, errStop)
+}
+
+func TestComposition(t *testing.T) {
+	var buffer bytes.Buffer
+	var attempts atomic.Int64
+	fn := Chain(func(ctx context.Context, name string) (string, error) {
+		if attempts.Add(1) == 1 {
+			return "", temporaryError{}
+		}
+		return strings.ToUpper(name), nil
+	},
+		Logging[string, string](newLogger(&buffer), "upper"),
+		Timeout[string, string](time.Second),
+		Retry[string, string](retry.WithBackoff(retry.Constant(time.Millisecond))),
+		ConcurrencyLimit[string, string](2),
+		RateLimit[string, string](1000, 10),
+		CircuitBreaker[string, string](NewBreaker()),
+	)
+
+	result, err := fn(context.Background(), "gopher")
+	require.NoError(t, err)
+	assert.Equal(t, "GOPHER", result)
+	assert.Equal(t, int64(2), attempts.Load())
+	assert.Equal(t, 1, strings.Count(buffer.String(), "\n"))
+}
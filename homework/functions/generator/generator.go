@@ -0,0 +1,192 @@
+package generator
+
+import (
+	"math/rand/v2"
+)
+
+// Generator produces values on demand and keeps its position between calls.
+// After Stop or the end of values Next always returns false.
+type Generator[T any] struct {
+	next func() (T, bool)
+	stop func()
+	done bool
+}
+
+// New creates a generator, stop releases resources of next and may be nil.
+func New[T any](next func() (T, bool), stop func()) *Generator[T] {
+	return &Generator[T]{next: next, stop: stop}
+}
+
+func (g *Generator[T]) Next() (T, bool) {
+	if g.done {
+		var zero T
+		return zero, false
+	}
+
+	value, ok := g.next()
+	if !ok {
+		g.Stop()
+	}
+	return value, ok
+}
+
+// Stop ends the generator early, it is safe to call it several times.
+func (g *Generator[T]) Stop() {
+	if g.done {
+		return
+	}
+	g.done = true
+	if g.stop != nil {
+		g.stop()
+	}
+}
+
+// Collect reads the rest of the values, the generator has to be finite.
+func (g *Generator[T]) Collect() []T {
+	var result []T
+	for {
+		value, ok := g.Next()
+		if !ok {
+			return result
+		}
+		result = append(result, value)
+	}
+}
+
+func FromSlice[T any](values []T) *Generator[T] {
+	idx := 0
+	return New(func() (T, bool) {
+		if idx >= len(values) {
+			var zero T
+			return zero, false
+		}
+		idx++
+		return values[idx-1], true
+	}, nil)
+}
+
+// Iterate is the endless seed, fn(seed), fn(fn(seed)), ... sequence.
+func Iterate[T any](seed T, fn func(T) T) *Generator[T] {
+	value, started := seed, false
+	return New(func() (T, bool) {
+		if started {
+			value = fn(value)
+		}
+		started = true
+		return value, true
+	}, nil)
+}
+
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64
+}
+
+// Range yields start, start+step, ... while the value is before end,
+// a negative step counts down. It panics on a zero step.
+func Range[T Number](start, end, step T) *Generator[T] {
+	if step == 0 {
+		panic("generator: range step must not be zero")
+	}
+
+	value, idx, last := start, T(0), false
+	return New(func() (T, bool) {
+		if last || step > 0 && value >= end || step < 0 && value <= end {
+			return value, false
+		}
+
+		result := value
+		idx++
+		// the value is computed from the index, so floats do not accumulate errors
+		value = start + idx*step
+		// integers wrap around near the limits of the type instead of passing end
+		last = step > 0 && value <= result || step < 0 && value >= result
+		return result, true
+	}, nil)
+}
+
+// Take stops the source after count values.
+func Take[T any](source *Generator[T], count int) *Generator[T] {
+	return New(func() (T, bool) {
+		if count <= 0 {
+			var zero T
+			return zero, false
+		}
+		count--
+		return source.Next()
+	}, source.Stop)
+}
+
+// Cycle repeats the values of a finite source endlessly.
+func Cycle[T any](source *Generator[T]) *Generator[T] {
+	var seen []T
+	idx := 0
+	return New(func() (T, bool) {
+		if value, ok := source.Next(); ok {
+			seen = append(seen, value)
+			return value, true
+		}
+		if len(seen) == 0 {
+			var zero T
+			return zero, false
+		}
+
+		value := seen[idx%len(seen)]
+		idx++
+		return value, true
+	}, source.Stop)
+}
+
+// Interleave takes values from the sources in turn, skipping the finished ones.
+func Interleave[T any](sources ...*Generator[T]) *Generator[T] {
+	active := append([]*Generator[T](nil), sources...)
+	idx := 0
+	return New(func() (T, bool) {
+		for len(active) != 0 {
+			idx %= len(active)
+			if value, ok := active[idx].Next(); ok {
+				idx++
+				return value, true
+			}
+			active = append(active[:idx], active[idx+1:]...)
+		}
+
+		var zero T
+		return zero, false
+	}, func() {
+		for _, source := range sources {
+			source.Stop()
+		}
+	})
+}
+
+// Seeded returns a PCG source, the same seed gives the same sequence.
+func Seeded(seed uint64) rand.Source {
+	return rand.NewPCG(seed, seed^0x9e3779b97f4a7c15)
+}
+
+// Random is the endless sequence of fn results over the source:
+//
+//	dice := Random(Seeded(42), func(r *rand.Rand) int { return r.IntN(6) + 1 })
+func Random[T any](source rand.Source, fn func(*rand.Rand) T) *Generator[T] {
+	random := rand.New(source)
+	return New(func() (T, bool) {
+		return fn(random), true
+	}, nil)
+}
+
+// Shuffle yields the values once in a random order, values is not modified.
+func Shuffle[T any](source rand.Source, values []T) *Generator[T] {
+	random := rand.New(source)
+	order := random.Perm(len(values))
+	idx := 0
+	return New(func() (T, bool) {
+		if idx >= len(order) {
+			var zero T
+			return zero, false
+		}
+		idx++
+		return values[order[idx-1]], true
+	}, nil)
+}
@@ -0,0 +1,198 @@
+package generator
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// go test -v -race .
+
+// mutate is the linear congruential step from lessons/functions/random_generator.
+func mutate(number int) int {
+	return (1664525*number + 1013904223) % 2147483647
+}
+
+func TestNextAndStop(t *testing.T) {
+	var stopped int
+	values := []int{1, 2, 3}
+	idx := 0
+	generator := New(func() (int, bool) {
+		if idx == len(values) {
+			return 0, false
+		}
+		idx++
+		return values[idx-1], true
+	}, func() { stopped++ })
+
+	value, ok := generator.Next()
+	require.True(t, ok)
+	assert.Equal(t, 1, value)
+
+	// the position is kept between the calls
+	value, ok = generator.Next()
+	require.True(t, ok)
+	assert.Equal(t, 2, value)
+
+	generator.Stop()
+	generator.Stop()
+	_, ok = generator.Next()
+	assert.False(t, ok)
+	assert.Equal(t, 1, stopped)
+	assert.Equal(t, 2, idx)
+
+	exhausted := New(func() (int, bool) { return 0, false }, func() { stopped++ })
+	assert.Empty(t, exhausted.Collect())
+	_, ok = exhausted.Next()
+	assert.False(t, ok)
+	assert.Equal(t, 2, stopped)
+}
+
+func TestRange(t *testing.T) {
+	tests := map[string]struct {
+		start, end, step int
+		result           []int
+	}{
+		"ascending":        {start: 0, end: 5, step: 1, result: []int{0, 1, 2, 3, 4}},
+		"with step":        {start: 1, end: 10, step: 3, result: []int{1, 4, 7}},
+		"descending":       {start: 5, end: 0, step: -2, result: []int{5, 3, 1}},
+		"empty":            {start: 5, end: 5, step: 1, result: nil},
+		"wrong direction":  {start: 0, end: 5, step: -1, result: nil},
+		"end is exclusive": {start: 0, end: 6, step: 2, result: []int{0, 2, 4}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.result, Range(test.start, test.end, test.step).Collect())
+		})
+	}
+
+	overflows := map[string]struct {
+		collect func() any
+		result  any
+	}{
+		"uint8 over max": {
+			collect: func() any { return Range[uint8](250, 255, 10).Collect() },
+			result:  []uint8{250},
+		},
+		"uint8 wraps after several steps": {
+			collect: func() any { return Range[uint8](0, 255, 100).Collect() },
+			result:  []uint8{0, 100, 200},
+		},
+		"uint8 up to max": {
+			collect: func() any { return Range[uint8](252, 255, 1).Collect() },
+			result:  []uint8{252, 253, 254},
+		},
+		"int8 over max": {
+			collect: func() any { return Range[int8](100, 127, 50).Collect() },
+			result:  []int8{100},
+		},
+		"int8 under min": {
+			collect: func() any { return Range[int8](-100, -128, -50).Collect() },
+			result:  []int8{-100},
+		},
+		"int16 under min": {
+			collect: func() any { return Range[int16](-32000, -32768, -500).Collect() },
+			result:  []int16{-32000, -32500},
+		},
+	}
+
+	for name, test := range overflows {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.result, test.collect())
+		})
+	}
+
+	assert.Equal(t, []float64{0, 0.1, 0.2, 0.30000000000000004, 0.4}, Range(0, 0.5, 0.1).Collect())
+	assert.Panics(t, func() { Range(0, 10, 0) })
+}
+
+func TestTake(t *testing.T) {
+	var stopped bool
+	counter := Iterate(100, func(number int) int { return number + 1 })
+	source := New(counter.Next, func() { stopped = true })
+
+	assert.Equal(t, []int{100, 101, 102}, Take(source, 3).Collect())
+	assert.True(t, stopped)
+
+	assert.Equal(t, []int{1, 2}, Take(FromSlice([]int{1, 2}), 5).Collect())
+	assert.Empty(t, Take(FromSlice([]int{1, 2}), 0).Collect())
+}
+
+func TestCycle(t *testing.T) {
+	assert.Equal(t, []string{"a", "b", "c", "a", "b", "c", "a"}, Take(Cycle(FromSlice([]string{"a", "b", "c"})), 7).Collect())
+	assert.Empty(t, Take(Cycle(FromSlice([]string{})), 3).Collect())
+
+	// the stop reaches the source even before it is exhausted
+	var stopped bool
+	source := Range(0, 10, 1)
+	cycle := Cycle(New(source.Next, func() { stopped = true }))
+	assert.Equal(t, []int{0, 1}, Take(cycle, 2).Collect())
+	assert.True(t, stopped)
+}
+
+func TestInterleave(t *testing.T) {
+	tests := map[string]struct {
+		sources [][]int
+		result  []int
+	}{
+		"same length":      {sources: [][]int{{1, 3, 5}, {2, 4, 6}}, result: []int{1, 2, 3, 4, 5, 6}},
+		"different length": {sources: [][]int{{1}, {2, 4, 6}, {3, 5}}, result: []int{1, 2, 3, 4, 5, 6}},
+		"with empty":       {sources: [][]int{{}, {1, 2}}, result: []int{1, 2}},
+		"no sources":       {sources: nil, result: nil},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var sources []*Generator[int]
+			for _, values := range test.sources {
+				sources = append(sources, FromSlice(values))
+			}
+			assert.Equal(t, test.result, Interleave(sources...).Collect())
+		})
+	}
+
+	var stopped int
+	endless := func(number int) *Generator[int] {
+		return New(func() (int, bool) { return number, true }, func() { stopped++ })
+	}
+	assert.Equal(t, []int{1, 2, 1, 2, 1}, Take(Interleave(endless(1), endless(2)), 5).Collect())
+	assert.Equal(t, 2, stopped)
+}
+
+func TestIterate(t *testing.T) {
+	assert.Equal(t, []int{1, 1015568748, 1586792639}, Take(Iterate(1, mutate), 3).Collect())
+}
+
+func TestRandom(t *testing.T) {
+	dice := func(seed uint64) *Generator[int] {
+		return Random(Seeded(seed), func(r *rand.Rand) int { return r.IntN(6) + 1 })
+	}
+
+	first := Take(dice(42), 20).Collect()
+	assert.Equal(t, first, Take(dice(42), 20).Collect())
+	assert.NotEqual(t, first, Take(dice(43), 20).Collect())
+	for _, value := range first {
+		assert.True(t, value >= 1 && value <= 6)
+	}
+
+	floats := Random(rand.NewPCG(1, 2), (*rand.Rand).Float64)
+	expected := rand.New(rand.NewPCG(1, 2))
+	for range 5 {
+		value, ok := floats.Next()
+		require.True(t, ok)
+		assert.Equal(t, expected.Float64(), value)
+	}
+}
+
+func TestShuffle(t *testing.T) {
+	values := []string{"a", "b", "c", "d", "e", "f"}
+	shuffled := Shuffle(Seeded(7), values).Collect()
+
+	assert.ElementsMatch(t, values, shuffled)
+	assert.Equal(t, shuffled, Shuffle(Seeded(7), values).Collect())
+	assert.Equal(t, []string{"a", "b", "c", "d", "e", "f"}, values)
+	assert.Empty(t, Shuffle(Seeded(7), []string{}).Collect())
+}
@@ -0,0 +1,270 @@
+package lazy
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"golang_course/homework/errors/panics"
+)
+
+var ErrCycle = errors.New("dependency cycle")
+
+// CycleError is returned instead of waiting for a value that depends on itself.
+type CycleError struct {
+	Path []string
+}
+
+func (e *CycleError) Error() string {
+	return ErrCycle.Error() + ": " + strings.Join(e.Path, " -> ")
+}
+
+func (e *CycleError) Unwrap() error {
+	return ErrCycle
+}
+
+type Option func(*config)
+
+type config struct {
+	name string
+}
+
+// WithName sets the name used in cycle errors.
+func WithName(name string) Option {
+	return func(c *config) {
+		c.name = name
+	}
+}
+
+// Evaluation is passed to the function of a lazy value, its dependencies are
+// read through it with Use.
+type Evaluation struct {
+	lazy   resetter
+	name   string
+	parent *Evaluation
+}
+
+func (e *Evaluation) contains(lazy resetter) bool {
+	for ; e != nil; e = e.parent {
+		if e.lazy == lazy {
+			return true
+		}
+	}
+	return false
+}
+
+// path lists the names from the outermost evaluation to last.
+func (e *Evaluation) path(last string) []string {
+	result := []string{last}
+	for ; e != nil; e = e.parent {
+		result = append(result, e.name)
+	}
+	for lhs, rhs := 0, len(result)-1; lhs < rhs; lhs, rhs = lhs+1, rhs-1 {
+		result[lhs], result[rhs] = result[rhs], result[lhs]
+	}
+	return result
+}
+
+type resetter interface {
+	Reset()
+}
+
+type flight[T any] struct {
+	owner      *Evaluation
+	done       chan struct{}
+	generation uint64
+	value      T
+	err        error
+}
+
+// Lazy computes its value once on the first Get, errors and panics are kept
+// as the result too. Concurrent callers wait for the running computation.
+type Lazy[T any] struct {
+	name string
+	fn   func(*Evaluation) (T, error)
+
+	mutex      sync.Mutex
+	computed   bool
+	value      T
+	err        error
+	flight     *flight[T]
+	generation uint64
+	dependents map[resetter]struct{}
+}
+
+func New[T any](fn func() (T, error), options ...Option) *Lazy[T] {
+	return Derive(func(*Evaluation) (T, error) {
+		return fn()
+	}, options...)
+}
+
+// Derive creates a value that depends on other lazy values:
+//
+//	total := Derive(func(evaluation *Evaluation) (int, error) {
+//		value, err := Use(evaluation, price)
+//		if err != nil {
+//			return 0, err
+//		}
+//		return value * count, nil
+//	})
+func Derive[T any](fn func(*Evaluation) (T, error), options ...Option) *Lazy[T] {
+	lazy := &Lazy[T]{fn: fn}
+	config := config{name: fmt.Sprintf("lazy(%p)", lazy)}
+	for _, option := range options {
+		option(&config)
+	}
+	lazy.name = config.name
+	return lazy
+}
+
+func (l *Lazy[T]) Get() (T, error) {
+	return l.get(nil)
+}
+
+// Reset drops the computed value together with the values that used it,
+// the next Get computes it again.
+func (l *Lazy[T]) Reset() {
+	l.mutex.Lock()
+	var zero T
+	l.computed, l.value, l.err = false, zero, nil
+	// a running computation still finishes for its callers but is not kept
+	l.flight = nil
+	l.generation++
+	dependents := l.dependents
+	l.dependents = nil
+	l.mutex.Unlock()
+
+	for dependent := range dependents {
+		dependent.Reset()
+	}
+}
+
+// Use reads dependency from the function of another lazy value, the
+// dependent value is reset together with the dependency.
+func Use[T any](evaluation *Evaluation, dependency *Lazy[T]) (T, error) {
+	if evaluation != nil {
+		dependency.mutex.Lock()
+		if dependency.dependents == nil {
+			dependency.dependents = make(map[resetter]struct{})
+		}
+		dependency.dependents[evaluation.lazy] = struct{}{}
+		dependency.mutex.Unlock()
+	}
+	return dependency.get(evaluation)
+}
+
+func (l *Lazy[T]) get(parent *Evaluation) (T, error) {
+	var zero T
+	if parent.contains(l) {
+		return zero, &CycleError{Path: parent.path(l.name)}
+	}
+
+	l.mutex.Lock()
+	if l.computed {
+		value, err := l.value, l.err
+		l.mutex.Unlock()
+		return value, err
+	}
+
+	if running := l.flight; running != nil {
+		l.mutex.Unlock()
+		if !waits.add(parent, running.owner) {
+			return zero, &CycleError{Path: parent.path(l.name)}
+		}
+		<-running.done
+		waits.remove(parent, running.owner)
+		return running.value, running.err
+	}
+
+	evaluation := &Evaluation{lazy: l, name: l.name, parent: parent}
+	current := &flight[T]{owner: evaluation, done: make(chan struct{}), generation: l.generation}
+	l.flight = current
+	l.mutex.Unlock()
+
+	// the new evaluation waits for nothing yet, so this edge can not close a cycle
+	waits.add(parent, evaluation)
+	current.err = panics.Catch(func() error {
+		var err error
+		current.value, err = l.fn(evaluation)
+		return err
+	})
+	waits.remove(parent, evaluation)
+
+	l.mutex.Lock()
+	if l.generation == current.generation {
+		l.computed, l.value, l.err = true, current.value, current.err
+	}
+	if l.flight == current {
+		l.flight = nil
+	}
+	l.mutex.Unlock()
+
+	close(current.done)
+	return current.value, current.err
+}
+
+// graph keeps which evaluations wait for which, so waiting for a value computed
+// by another goroutine fails when that goroutine waits for us in turn.
+type graph struct {
+	mutex sync.Mutex
+	edges map[*Evaluation]map[*Evaluation]int
+}
+
+var waits = graph{edges: make(map[*Evaluation]map[*Evaluation]int)}
+
+// add records that from waits for to, unless to already waits for from.
+// Top level calls have no evaluation, nothing can wait for them.
+func (g *graph) add(from, to *Evaluation) bool {
+	if from == nil {
+		return true
+	}
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if g.reachable(to, from) {
+		return false
+	}
+	if g.edges[from] == nil {
+		g.edges[from] = make(map[*Evaluation]int)
+	}
+	g.edges[from][to]++
+	return true
+}
+
+func (g *graph) remove(from, to *Evaluation) {
+	if from == nil {
+		return
+	}
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	g.edges[from][to]--
+	if g.edges[from][to] == 0 {
+		delete(g.edges[from], to)
+	}
+	if len(g.edges[from]) == 0 {
+		delete(g.edges, from)
+	}
+}
+
+func (g *graph) reachable(from, target *Evaluation) bool {
+	visited := map[*Evaluation]bool{from: true}
+	stack := []*Evaluation{from}
+	for len(stack) != 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if current == target {
+			return true
+		}
+		for next := range g.edges[current] {
+			if !visited[next] {
+				visited[next] = true
+				stack = append(stack, next)
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,229 @@
+package lazy
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang_course/homework/errors/panics"
+)
+
+// go test -v -race .
+
+func TestGetOnce(t *testing.T) {
+	var calls atomic.Int64
+	value := New(func() (int, error) {
+		calls.Add(1)
+		time.Sleep(10 * time.Millisecond)
+		return 42, nil
+	})
+	assert.Zero(t, calls.Load())
+
+	var wg sync.WaitGroup
+	results := make([]int, 20)
+	for idx := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[idx], _ = value.Get()
+		}()
+	}
+	wg.Wait()
+
+	for _, result := range results {
+		assert.Equal(t, 42, result)
+	}
+	assert.Equal(t, int64(1), calls.Load())
+}
+
+func TestErrors(t *testing.T) {
+	errFailed := errors.New("failed")
+	tests := map[string]struct {
+		fn    func() (string, error)
+		check func(t *testing.T, err error)
+	}{
+		"error": {
+			fn: func() (string, error) { return "", errFailed },
+			check: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, errFailed)
+			},
+		},
+		"panic": {
+			fn: func() (string, error) { panic("broken") },
+			check: func(t *testing.T, err error) {
+				var panicErr *panics.PanicError
+				require.ErrorAs(t, err, &panicErr)
+				assert.Equal(t, "broken", panicErr.Value)
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			calls := 0
+			value := New(func() (string, error) {
+				calls++
+				return test.fn()
+			})
+
+			for range 3 {
+				_, err := value.Get()
+				test.check(t, err)
+			}
+			assert.Equal(t, 1, calls)
+		})
+	}
+}
+
+func TestDependencies(t *testing.T) {
+	var calls atomic.Int64
+	base := New(func() (int, error) {
+		calls.Add(1)
+		return 10, nil
+	})
+	double := Derive(func(evaluation *Evaluation) (int, error) {
+		value, err := Use(evaluation, base)
+		return value * 2, err
+	})
+	increment := Derive(func(evaluation *Evaluation) (int, error) {
+		value, err := Use(evaluation, base)
+		return value + 1, err
+	})
+	total := Derive(func(evaluation *Evaluation) (int, error) {
+		lhs, err := Use(evaluation, double)
+		if err != nil {
+			return 0, err
+		}
+		rhs, err := Use(evaluation, increment)
+		return lhs + rhs, err
+	})
+
+	result, err := total.Get()
+	require.NoError(t, err)
+	assert.Equal(t, 31, result)
+	assert.Equal(t, int64(1), calls.Load())
+}
+
+func TestReset(t *testing.T) {
+	price := 100
+	priceCalls, totalCalls := 0, 0
+	cost := New(func() (int, error) {
+		priceCalls++
+		return price, nil
+	})
+	total := Derive(func(evaluation *Evaluation) (int, error) {
+		totalCalls++
+		value, err := Use(evaluation, cost)
+		return value * 3, err
+	})
+
+	result, _ := total.Get()
+	assert.Equal(t, 300, result)
+
+	// resetting the dependency resets the values that used it
+	price = 200
+	cost.Reset()
+	result, _ = total.Get()
+	assert.Equal(t, 600, result)
+	assert.Equal(t, 2, priceCalls)
+	assert.Equal(t, 2, totalCalls)
+
+	// the dependency is kept when only the dependent value is reset
+	total.Reset()
+	result, _ = total.Get()
+	assert.Equal(t, 600, result)
+	assert.Equal(t, 2, priceCalls)
+	assert.Equal(t, 3, totalCalls)
+}
+
+func TestResetWhileComputing(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	var calls atomic.Int64
+	value := New(func() (int64, error) {
+		call := calls.Add(1)
+		if call == 1 {
+			close(started)
+			<-release
+		}
+		return call, nil
+	})
+
+	done := make(chan int64)
+	go func() {
+		result, _ := value.Get()
+		done <- result
+	}()
+
+	<-started
+	value.Reset()
+	close(release)
+	assert.Equal(t, int64(1), <-done)
+
+	// the result of the computation started before Reset is not kept
+	result, err := value.Get()
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), result)
+}
+
+func TestCycle(t *testing.T) {
+	var self *Lazy[int]
+	self = Derive(func(evaluation *Evaluation) (int, error) {
+		return Use(evaluation, self)
+	}, WithName("self"))
+
+	_, err := self.Get()
+	var cycleErr *CycleError
+	require.ErrorAs(t, err, &cycleErr)
+	assert.Equal(t, []string{"self", "self"}, cycleErr.Path)
+
+	var first, second *Lazy[string]
+	first = Derive(func(evaluation *Evaluation) (string, error) {
+		return Use(evaluation, second)
+	}, WithName("first"))
+	second = Derive(func(evaluation *Evaluation) (string, error) {
+		return Use(evaluation, first)
+	}, WithName("second"))
+
+	_, err = first.Get()
+	assert.ErrorIs(t, err, ErrCycle)
+	assert.EqualError(t, err, "dependency cycle: first -> second -> first")
+}
+
+func TestConcurrentCycle(t *testing.T) {
+	var started sync.WaitGroup
+	started.Add(2)
+
+	var first, second *Lazy[int]
+	first = Derive(func(evaluation *Evaluation) (int, error) {
+		started.Done()
+		started.Wait()
+		return Use(evaluation, second)
+	})
+	second = Derive(func(evaluation *Evaluation) (int, error) {
+		started.Done()
+		started.Wait()
+		return Use(evaluation, first)
+	})
+
+	errs := make(chan error, 2)
+	for _, value := range []*Lazy[int]{first, second} {
+		go func() {
+			_, err := value.Get()
+			errs <- err
+		}()
+	}
+
+	for range 2 {
+		select {
+		case err := <-errs:
+			assert.ErrorIs(t, err, ErrCycle)
+		case <-time.After(time.Second):
+			t.Fatal("cycle was not detected")
+		}
+	}
+}
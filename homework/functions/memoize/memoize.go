@@ -0,0 +1,177 @@
+package memoize
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"golang_course/homework/errors/panics"
+)
+
+type Stats struct {
+	Hits      uint64 // served from the cache
+	Misses    uint64 // the function was called
+	Shared    uint64 // waited for a call already running for the key
+	Evictions uint64 // removed because of the size limit or the TTL
+	Size      int
+}
+
+type Option func(*config)
+
+type config struct {
+	maxSize     int
+	ttl         time.Duration
+	cacheErrors bool
+	now         func() time.Time
+}
+
+// WithMaxSize bounds the cache, the least recently used entry is evicted first.
+func WithMaxSize(size int) Option {
+	return func(c *config) {
+		c.maxSize = size
+	}
+}
+
+func WithTTL(ttl time.Duration) Option {
+	return func(c *config) {
+		c.ttl = ttl
+	}
+}
+
+// WithErrorCaching keeps failed results too, by default the next call retries.
+func WithErrorCaching() Option {
+	return func(c *config) {
+		c.cacheErrors = true
+	}
+}
+
+func WithClock(now func() time.Time) Option {
+	return func(c *config) {
+		c.now = now
+	}
+}
+
+type entry[K comparable, V any] struct {
+	key     K
+	value   V
+	err     error
+	expires time.Time
+}
+
+type call[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+// Memo caches results of fn. Concurrent calls for the same key run fn once,
+// a panic in fn is returned to every caller as *panics.PanicError.
+type Memo[K comparable, V any] struct {
+	fn     func(K) (V, error)
+	config config
+
+	mutex   sync.Mutex
+	entries map[K]*list.Element
+	order   *list.List // front is the most recently used
+	calls   map[K]*call[V]
+	stats   Stats
+}
+
+func New[K comparable, V any](fn func(K) (V, error), opts ...Option) *Memo[K, V] {
+	memo := &Memo[K, V]{
+		fn:      fn,
+		config:  config{now: time.Now},
+		entries: make(map[K]*list.Element),
+		order:   list.New(),
+		calls:   make(map[K]*call[V]),
+	}
+	for _, opt := range opts {
+		opt(&memo.config)
+	}
+	return memo
+}
+
+// Memoize is a shortcut for New(fn, opts...).Get.
+func Memoize[K comparable, V any](fn func(K) (V, error), opts ...Option) func(K) (V, error) {
+	return New(fn, opts...).Get
+}
+
+func (m *Memo[K, V]) Get(key K) (V, error) {
+	m.mutex.Lock()
+	if element, found := m.entries[key]; found {
+		cached := element.Value.(*entry[K, V])
+		if m.config.ttl == 0 || m.config.now().Before(cached.expires) {
+			m.order.MoveToFront(element)
+			m.stats.Hits++
+			m.mutex.Unlock()
+			return cached.value, cached.err
+		}
+		m.remove(element)
+		m.stats.Evictions++
+	}
+
+	if running, found := m.calls[key]; found {
+		m.stats.Shared++
+		m.mutex.Unlock()
+		<-running.done
+		return running.value, running.err
+	}
+
+	running := &call[V]{done: make(chan struct{})}
+	m.calls[key] = running
+	m.stats.Misses++
+	m.mutex.Unlock()
+
+	running.err = panics.Catch(func() error {
+		var err error
+		running.value, err = m.fn(key)
+		return err
+	})
+
+	m.mutex.Lock()
+	delete(m.calls, key)
+	if running.err == nil || m.config.cacheErrors {
+		m.store(key, running.value, running.err)
+	}
+	m.mutex.Unlock()
+
+	close(running.done)
+	return running.value, running.err
+}
+
+// Forget drops the cached result of key.
+func (m *Memo[K, V]) Forget(key K) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if element, found := m.entries[key]; found {
+		m.remove(element)
+	}
+}
+
+func (m *Memo[K, V]) Stats() Stats {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	stats := m.stats
+	stats.Size = m.order.Len()
+	return stats
+}
+
+func (m *Memo[K, V]) store(key K, value V, err error) {
+	cached := &entry[K, V]{key: key, value: value, err: err}
+	if m.config.ttl != 0 {
+		cached.expires = m.config.now().Add(m.config.ttl)
+	}
+	m.entries[key] = m.order.PushFront(cached)
+
+	for m.config.maxSize > 0 && m.order.Len() > m.config.maxSize {
+		m.remove(m.order.Back())
+		m.stats.Evictions++
+	}
+}
+
+func (m *Memo[K, V]) remove(element *list.Element) {
+	m.order.Remove(element)
+	delete(m.entries, element.Value.(*entry[K, V]).key)
+}
@@ -0,0 +1,178 @@
+package memoize
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang_course/homework/errors/panics"
+)
+
+// go test -v -race .
+
+func TestFibonacci(t *testing.T) {
+	var calls int
+	var fibonacci *Memo[int, int]
+	fibonacci = New(func(number int) (int, error) {
+		calls++
+		if number <= 2 {
+			return 1, nil
+		}
+
+		lhs, _ := fibonacci.Get(number - 1)
+		rhs, _ := fibonacci.Get(number - 2)
+		return lhs + rhs, nil
+	})
+
+	result, err := fibonacci.Get(90)
+	require.NoError(t, err)
+	assert.Equal(t, 2880067194370816120, result)
+	assert.Equal(t, 90, calls)
+
+	result, _ = fibonacci.Get(50)
+	assert.Equal(t, 12586269025, result)
+	assert.Equal(t, 90, calls)
+
+	stats := fibonacci.Stats()
+	assert.Equal(t, uint64(90), stats.Misses)
+	assert.Equal(t, uint64(88), stats.Hits)
+	assert.Equal(t, 90, stats.Size)
+}
+
+func TestSingleFlight(t *testing.T) {
+	var calls atomic.Int64
+	release := make(chan struct{})
+	memo := New(func(key string) (int, error) {
+		calls.Add(1)
+		<-release
+		return len(key), nil
+	})
+
+	const callers = 50
+	var started, wg sync.WaitGroup
+	started.Add(callers)
+	results := make([]int, callers)
+	for idx := range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			started.Done()
+			results[idx], _ = memo.Get("golang")
+		}()
+	}
+
+	started.Wait()
+	assert.Eventually(t, func() bool {
+		stats := memo.Stats()
+		return stats.Misses+stats.Shared == callers
+	}, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int64(1), calls.Load())
+	for _, result := range results {
+		assert.Equal(t, 6, result)
+	}
+
+	stats := memo.Stats()
+	assert.Equal(t, uint64(1), stats.Misses)
+	assert.Equal(t, uint64(callers-1), stats.Shared)
+}
+
+func TestLRU(t *testing.T) {
+	var calls []int
+	memo := New(func(key int) (int, error) {
+		calls = append(calls, key)
+		return key * key, nil
+	}, WithMaxSize(2))
+
+	for _, key := range []int{1, 2, 1, 3, 1, 2} {
+		result, err := memo.Get(key)
+		require.NoError(t, err)
+		assert.Equal(t, key*key, result)
+	}
+
+	// 2 is evicted by 3 because 1 was used later, then 3 is evicted by 2
+	assert.Equal(t, []int{1, 2, 3, 2}, calls)
+	assert.Equal(t, Stats{Hits: 2, Misses: 4, Evictions: 2, Size: 2}, memo.Stats())
+}
+
+func TestTTL(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var calls int
+	memo := New(func(key string) (string, error) {
+		calls++
+		return key + "!", nil
+	}, WithTTL(time.Minute), WithClock(func() time.Time { return now }))
+
+	memo.Get("a")
+	now = now.Add(30 * time.Second)
+	memo.Get("a")
+	assert.Equal(t, 1, calls)
+
+	now = now.Add(30 * time.Second)
+	result, _ := memo.Get("a")
+	assert.Equal(t, "a!", result)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, Stats{Hits: 1, Misses: 2, Evictions: 1, Size: 1}, memo.Stats())
+
+	memo.Forget("a")
+	memo.Get("a")
+	assert.Equal(t, 3, calls)
+}
+
+func TestErrors(t *testing.T) {
+	tests := map[string]struct {
+		options []Option
+		calls   int
+	}{
+		"errors are retried": {
+			calls: 3,
+		},
+		"errors are cached": {
+			options: []Option{WithErrorCaching()},
+			calls:   1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			calls := 0
+			memo := Memoize(func(int) (int, error) {
+				calls++
+				return 0, io.ErrUnexpectedEOF
+			}, test.options...)
+
+			for range 3 {
+				_, err := memo(1)
+				assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+			}
+			assert.Equal(t, test.calls, calls)
+		})
+	}
+}
+
+func TestPanic(t *testing.T) {
+	memo := New(func(key int) (int, error) {
+		if key == 0 {
+			panic("zero key")
+		}
+		return key, nil
+	})
+
+	_, err := memo.Get(0)
+	var panicErr *panics.PanicError
+	require.True(t, errors.As(err, &panicErr))
+	assert.Equal(t, "zero key", panicErr.Value)
+
+	// the failed call does not block the key
+	_, err = memo.Get(0)
+	assert.Error(t, err)
+	assert.Equal(t, uint64(2), memo.Stats().Misses)
+}
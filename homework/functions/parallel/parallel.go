@@ -0,0 +1,262 @@
+package parallel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"golang_course/homework/errors/panics"
+)
+
+// ItemError tells which input element failed, panics come as *panics.PanicError.
+type ItemError struct {
+	Index int
+	Err   error
+}
+
+func (e *ItemError) Error() string {
+	return fmt.Sprintf("item %d: %v", e.Index, e.Err)
+}
+
+func (e *ItemError) Unwrap() error {
+	return e.Err
+}
+
+// errStopped marks a chunk left unfinished because the context was canceled.
+var errStopped = errors.New("stopped")
+
+type Option func(*config)
+
+type config struct {
+	workers   int
+	chunkSize int
+	unordered bool
+}
+
+// WithWorkers sets the number of goroutines, GOMAXPROCS by default.
+func WithWorkers(workers int) Option {
+	return func(c *config) {
+		if workers > 0 {
+			c.workers = workers
+		}
+	}
+}
+
+// WithChunkSize sets how many elements a worker takes at once,
+// by default every worker gets about four chunks.
+func WithChunkSize(size int) Option {
+	return func(c *config) {
+		if size > 0 {
+			c.chunkSize = size
+		}
+	}
+}
+
+// WithUnordered lets Filter return values in the order chunks are finished.
+func WithUnordered() Option {
+	return func(c *config) {
+		c.unordered = true
+	}
+}
+
+func newConfig(count int, opts []Option) *config {
+	cfg := &config{workers: runtime.GOMAXPROCS(0)}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.chunkSize == 0 {
+		cfg.chunkSize = max(1, count/(cfg.workers*4))
+	}
+	return cfg
+}
+
+// Map applies action to every element, the result keeps the input order.
+// The first failure stops the remaining work and is returned as *ItemError,
+// a canceled context is reported with its cause.
+func Map[T, U any](ctx context.Context, data []T, action func(T) (U, error), opts ...Option) ([]U, error) {
+	cfg := newConfig(len(data), opts)
+	result := make([]U, len(data))
+
+	err := run(ctx, len(data), cfg, func(ctx context.Context, _, start, end int) error {
+		return forEach(ctx, start, end, func(idx int) error {
+			value, err := action(data[idx])
+			result[idx] = value
+			return err
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func Filter[T any](ctx context.Context, data []T, predicate func(T) (bool, error), opts ...Option) ([]T, error) {
+	cfg := newConfig(len(data), opts)
+	chunks := make([][]T, chunkCount(len(data), cfg.chunkSize))
+
+	var mutex sync.Mutex
+	var unordered []T
+	err := run(ctx, len(data), cfg, func(ctx context.Context, chunk, start, end int) error {
+		var kept []T
+		err := forEach(ctx, start, end, func(idx int) error {
+			keep, err := predicate(data[idx])
+			if keep {
+				kept = append(kept, data[idx])
+			}
+			return err
+		})
+
+		if cfg.unordered {
+			mutex.Lock()
+			unordered = append(unordered, kept...)
+			mutex.Unlock()
+		} else {
+			chunks[chunk] = kept
+		}
+		return err
+	})
+
+	switch {
+	case err != nil:
+		return nil, err
+	case cfg.unordered:
+		return unordered, nil
+	}
+
+	var result []T
+	for _, kept := range chunks {
+		result = append(result, kept...)
+	}
+	return result, nil
+}
+
+// Reduce folds every chunk sequentially and then merges the partial results
+// pairwise, level by level. combine has to be associative and identity has
+// to be its neutral element, it is the result for empty data.
+func Reduce[T any](ctx context.Context, data []T, identity T, combine func(T, T) (T, error), opts ...Option) (T, error) {
+	if len(data) == 0 {
+		return identity, nil
+	}
+
+	cfg := newConfig(len(data), opts)
+	partials := make([]T, chunkCount(len(data), cfg.chunkSize))
+
+	err := run(ctx, len(data), cfg, func(ctx context.Context, chunk, start, end int) error {
+		// identity is folded into the first chunk only
+		result, from := data[start], start+1
+		if chunk == 0 {
+			result, from = identity, start
+		}
+		err := forEach(ctx, from, end, func(idx int) error {
+			var err error
+			result, err = combine(result, data[idx])
+			return err
+		})
+		partials[chunk] = result
+		return err
+	})
+	if err != nil {
+		return identity, err
+	}
+
+	merge := &config{workers: cfg.workers, chunkSize: 1}
+	for len(partials) > 1 {
+		merged := make([]T, (len(partials)+1)/2)
+		if len(partials)%2 != 0 {
+			merged[len(merged)-1] = partials[len(partials)-1]
+		}
+
+		err := run(ctx, len(partials)/2, merge, func(_ context.Context, pair, _, _ int) error {
+			return panics.Catch(func() error {
+				var err error
+				merged[pair], err = combine(partials[2*pair], partials[2*pair+1])
+				return err
+			})
+		})
+		if err != nil {
+			return identity, err
+		}
+		partials = merged
+	}
+
+	return partials[0], nil
+}
+
+// forEach stops on the first error, a panic is reported for the element that caused it.
+func forEach(ctx context.Context, start, end int, action func(idx int) error) error {
+	idx := start
+	err := panics.Catch(func() error {
+		for ; idx < end; idx++ {
+			if ctx.Err() != nil {
+				return errStopped
+			}
+			if err := action(idx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil && err != errStopped {
+		return &ItemError{Index: idx, Err: err}
+	}
+	return err
+}
+
+func chunkCount(count, size int) int {
+	return (count + size - 1) / size
+}
+
+// run hands out chunks of [0, count) to the workers. The first failure
+// cancels the others, the failure of the smallest chunk is returned.
+func run(parent context.Context, count int, cfg *config, process func(ctx context.Context, chunk, start, end int) error) error {
+	ctx, cancel := context.WithCancelCause(parent)
+	defer cancel(nil)
+
+	chunks := chunkCount(count, cfg.chunkSize)
+	var next, done atomic.Int64
+
+	var mutex sync.Mutex
+	failedChunk, failure := chunks, error(nil)
+
+	var wg sync.WaitGroup
+	for range min(cfg.workers, chunks) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				chunk := int(next.Add(1) - 1)
+				if chunk >= chunks {
+					return
+				}
+
+				start := chunk * cfg.chunkSize
+				err := process(ctx, chunk, start, min(start+cfg.chunkSize, count))
+				if err == errStopped {
+					return
+				}
+				if err != nil {
+					mutex.Lock()
+					if chunk < failedChunk {
+						failedChunk, failure = chunk, err
+					}
+					mutex.Unlock()
+					cancel(err)
+					return
+				}
+				done.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	switch {
+	case failure != nil:
+		return failure
+	case int(done.Load()) < chunks:
+		return context.Cause(parent)
+	}
+	return nil
+}
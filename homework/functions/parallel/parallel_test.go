@@ -0,0 +1,216 @@
+package parallel
+
+import (
+	"context"
+	"errors"
+	"io"
+	"runtime"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang_course/homework/errors/panics"
+)
+
+// go test -v -race -bench=. .
+
+func numbers(count int) []int {
+	result := make([]int, count)
+	for idx := range result {
+		result[idx] = idx + 1
+	}
+	return result
+}
+
+func TestMap(t *testing.T) {
+	tests := map[string]struct {
+		data    []int
+		options []Option
+	}{
+		"nil numbers":    {},
+		"empty numbers":  {data: []int{}},
+		"one worker":     {data: numbers(100), options: []Option{WithWorkers(1)}},
+		"many workers":   {data: numbers(1000), options: []Option{WithWorkers(8)}},
+		"single chunks":  {data: numbers(50), options: []Option{WithWorkers(4), WithChunkSize(1)}},
+		"uneven chunks":  {data: numbers(103), options: []Option{WithWorkers(3), WithChunkSize(10)}},
+		"more workers":   {data: numbers(3), options: []Option{WithWorkers(16)}},
+		"default config": {data: numbers(10_000)},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := Map(context.Background(), test.data, func(number int) (string, error) {
+				return strconv.Itoa(number * number), nil
+			}, test.options...)
+			require.NoError(t, err)
+
+			require.Len(t, result, len(test.data))
+			for idx, number := range test.data {
+				assert.Equal(t, strconv.Itoa(number*number), result[idx])
+			}
+		})
+	}
+}
+
+func TestFilter(t *testing.T) {
+	isEven := func(number int) (bool, error) { return number%2 == 0, nil }
+
+	result, err := Filter(context.Background(), numbers(1000), isEven, WithWorkers(4), WithChunkSize(7))
+	require.NoError(t, err)
+	require.Len(t, result, 500)
+	for idx, number := range result {
+		assert.Equal(t, 2*(idx+1), number)
+	}
+
+	unordered, err := Filter(context.Background(), numbers(1000), isEven, WithWorkers(4), WithChunkSize(7), WithUnordered())
+	require.NoError(t, err)
+	assert.ElementsMatch(t, result, unordered)
+
+	result, err = Filter(context.Background(), []int{1, 3}, isEven)
+	require.NoError(t, err)
+	assert.Empty(t, result)
+}
+
+func TestReduce(t *testing.T) {
+	sum := func(lhs, rhs int) (int, error) { return lhs + rhs, nil }
+	for _, count := range []int{0, 1, 2, 3, 7, 100, 1001} {
+		for _, chunkSize := range []int{1, 2, 5, 64} {
+			result, err := Reduce(context.Background(), numbers(count), 0, sum, WithWorkers(4), WithChunkSize(chunkSize))
+			require.NoError(t, err)
+			assert.Equal(t, count*(count+1)/2, result, "count %d chunk %d", count, chunkSize)
+		}
+	}
+
+	// string concatenation is associative but not commutative, so the order has to be kept
+	words := make([]string, 200)
+	expected := ""
+	for idx := range words {
+		words[idx] = strconv.Itoa(idx) + ","
+		expected += words[idx]
+	}
+	concat := func(lhs, rhs string) (string, error) { return lhs + rhs, nil }
+	result, err := Reduce(context.Background(), words, "", concat, WithWorkers(8), WithChunkSize(3))
+	require.NoError(t, err)
+	assert.Equal(t, expected, result)
+
+	empty, err := Reduce(context.Background(), []int{}, 1, sum)
+	require.NoError(t, err)
+	assert.Equal(t, 1, empty)
+}
+
+func TestErrors(t *testing.T) {
+	var processed atomic.Int64
+	_, err := Map(context.Background(), numbers(10_000), func(number int) (int, error) {
+		processed.Add(1)
+		if number == 42 {
+			return 0, io.ErrUnexpectedEOF
+		}
+		return number, nil
+	}, WithWorkers(4), WithChunkSize(10))
+
+	var itemErr *ItemError
+	require.ErrorAs(t, err, &itemErr)
+	assert.Equal(t, 41, itemErr.Index)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	assert.Less(t, processed.Load(), int64(10_000))
+
+	_, err = Filter(context.Background(), numbers(100), func(number int) (bool, error) {
+		if number == 7 {
+			var values []int
+			return values[number] == 0, nil
+		}
+		return true, nil
+	}, WithWorkers(2), WithChunkSize(5))
+
+	require.ErrorAs(t, err, &itemErr)
+	assert.Equal(t, 6, itemErr.Index)
+	var panicErr *panics.PanicError
+	require.ErrorAs(t, err, &panicErr)
+	var runtimeErr runtime.Error
+	assert.ErrorAs(t, err, &runtimeErr)
+
+	_, err = Reduce(context.Background(), numbers(64), 0, func(lhs, rhs int) (int, error) {
+		if lhs > 500 {
+			panic("sum is too large")
+		}
+		return lhs + rhs, nil
+	}, WithWorkers(4), WithChunkSize(4))
+	require.ErrorAs(t, err, &panicErr)
+	assert.Equal(t, "sum is too large", panicErr.Value)
+}
+
+func TestCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	errShutdown := errors.New("shutdown")
+
+	var processed atomic.Int64
+	_, err := Map(ctx, numbers(10_000), func(number int) (int, error) {
+		if processed.Add(1) == 100 {
+			cancel(errShutdown)
+		}
+		return number, nil
+	}, WithWorkers(4), WithChunkSize(10))
+
+	assert.ErrorIs(t, err, errShutdown)
+	assert.Less(t, processed.Load(), int64(10_000))
+
+	_, err = Reduce(ctx, numbers(10), 0, func(lhs, rhs int) (int, error) { return lhs + rhs, nil })
+	assert.ErrorIs(t, err, errShutdown)
+}
+
+// cpuHeavy imitates an expensive transformation
+func cpuHeavy(number int) int {
+	result := number
+	for range 2_000 {
+		result = result*1_103_515_245 + 12_345
+	}
+	return result
+}
+
+var (
+	benchmarkData = numbers(20_000)
+	benchmarkSink int
+)
+
+func BenchmarkSequentialMap(b *testing.B) {
+	for range b.N {
+		result := make([]int, len(benchmarkData))
+		for idx, number := range benchmarkData {
+			result[idx] = cpuHeavy(number)
+		}
+		benchmarkSink = result[0]
+	}
+}
+
+func BenchmarkParallelMap(b *testing.B) {
+	for range b.N {
+		result, _ := Map(context.Background(), benchmarkData, func(number int) (int, error) {
+			return cpuHeavy(number), nil
+		})
+		benchmarkSink = result[0]
+	}
+}
+
+func BenchmarkSequentialMapReduce(b *testing.B) {
+	for range b.N {
+		result := 0
+		for _, number := range benchmarkData {
+			result += cpuHeavy(number)
+		}
+		benchmarkSink = result
+	}
+}
+
+func BenchmarkParallelMapReduce(b *testing.B) {
+	for range b.N {
+		mapped, _ := Map(context.Background(), benchmarkData, func(number int) (int, error) {
+			return cpuHeavy(number), nil
+		})
+		benchmarkSink, _ = Reduce(context.Background(), mapped, 0, func(lhs, rhs int) (int, error) {
+			return lhs + rhs, nil
+		})
+	}
+}
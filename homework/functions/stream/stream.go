@@ -0,0 +1,212 @@
+package stream
+
+// Stream is a lazy pull based sequence, nothing is computed until the
+// values are requested by Next or by a terminal operation like Collect.
+// A stream is consumed once, stages hold their own state.
+type Stream[T any] struct {
+	next func() (T, bool)
+}
+
+func FromSlice[T any](values []T) Stream[T] {
+	idx := 0
+	return Stream[T]{next: func() (T, bool) {
+		if idx >= len(values) {
+			var zero T
+			return zero, false
+		}
+		idx++
+		return values[idx-1], true
+	}}
+}
+
+// FromChannel reads until the channel is closed.
+func FromChannel[T any](values <-chan T) Stream[T] {
+	return Stream[T]{next: func() (T, bool) {
+		value, ok := <-values
+		return value, ok
+	}}
+}
+
+// Generate uses fn as the source, the stream ends when fn returns false.
+func Generate[T any](fn func() (T, bool)) Stream[T] {
+	return Stream[T]{next: fn}
+}
+
+// Iterate returns the infinite stream seed, fn(seed), fn(fn(seed)), ...
+func Iterate[T any](seed T, fn func(T) T) Stream[T] {
+	value, started := seed, false
+	return Stream[T]{next: func() (T, bool) {
+		if started {
+			value = fn(value)
+		}
+		started = true
+		return value, true
+	}}
+}
+
+func (s Stream[T]) Next() (T, bool) {
+	return s.next()
+}
+
+func (s Stream[T]) Filter(predicate func(T) bool) Stream[T] {
+	return Stream[T]{next: func() (T, bool) {
+		for {
+			value, ok := s.next()
+			if !ok || predicate(value) {
+				return value, ok
+			}
+		}
+	}}
+}
+
+// Take stops pulling from the source after count values.
+func (s Stream[T]) Take(count int) Stream[T] {
+	return Stream[T]{next: func() (T, bool) {
+		if count <= 0 {
+			var zero T
+			return zero, false
+		}
+		count--
+		return s.next()
+	}}
+}
+
+func (s Stream[T]) Skip(count int) Stream[T] {
+	return Stream[T]{next: func() (T, bool) {
+		for ; count > 0; count-- {
+			if _, ok := s.next(); !ok {
+				var zero T
+				return zero, false
+			}
+		}
+		return s.next()
+	}}
+}
+
+// ForEach stops as soon as action returns false.
+func (s Stream[T]) ForEach(action func(T) bool) {
+	for {
+		value, ok := s.next()
+		if !ok || !action(value) {
+			return
+		}
+	}
+}
+
+func (s Stream[T]) Collect() []T {
+	var result []T
+	for {
+		value, ok := s.next()
+		if !ok {
+			return result
+		}
+		result = append(result, value)
+	}
+}
+
+func (s Stream[T]) First() (T, bool) {
+	return s.next()
+}
+
+// Any stops at the first matching value.
+func (s Stream[T]) Any(predicate func(T) bool) bool {
+	_, found := s.Filter(predicate).First()
+	return found
+}
+
+func Map[T, U any](s Stream[T], action func(T) U) Stream[U] {
+	return Stream[U]{next: func() (U, bool) {
+		value, ok := s.next()
+		if !ok {
+			var zero U
+			return zero, false
+		}
+		return action(value), true
+	}}
+}
+
+func FlatMap[T, U any](s Stream[T], action func(T) Stream[U]) Stream[U] {
+	current := Stream[U]{next: func() (U, bool) {
+		var zero U
+		return zero, false
+	}}
+	return Stream[U]{next: func() (U, bool) {
+		for {
+			if value, ok := current.next(); ok {
+				return value, true
+			}
+
+			value, ok := s.next()
+			if !ok {
+				var zero U
+				return zero, false
+			}
+			current = action(value)
+		}
+	}}
+}
+
+// Chunk groups values into slices of size, the last one can be shorter.
+func Chunk[T any](s Stream[T], size int) Stream[[]T] {
+	if size <= 0 {
+		panic("stream: chunk size must be positive")
+	}
+
+	return Stream[[]T]{next: func() ([]T, bool) {
+		var chunk []T
+		for len(chunk) < size {
+			value, ok := s.next()
+			if !ok {
+				break
+			}
+			if chunk == nil {
+				chunk = make([]T, 0, size)
+			}
+			chunk = append(chunk, value)
+		}
+		return chunk, chunk != nil
+	}}
+}
+
+// Distinct remembers every value it has returned.
+func Distinct[T comparable](s Stream[T]) Stream[T] {
+	seen := make(map[T]struct{})
+	return s.Filter(func(value T) bool {
+		if _, found := seen[value]; found {
+			return false
+		}
+		seen[value] = struct{}{}
+		return true
+	})
+}
+
+type Pair[T, U any] struct {
+	First  T
+	Second U
+}
+
+// Zip ends with the shorter stream.
+func Zip[T, U any](lhs Stream[T], rhs Stream[U]) Stream[Pair[T, U]] {
+	return Stream[Pair[T, U]]{next: func() (Pair[T, U], bool) {
+		first, ok := lhs.next()
+		if !ok {
+			return Pair[T, U]{}, false
+		}
+		second, ok := rhs.next()
+		if !ok {
+			return Pair[T, U]{}, false
+		}
+		return Pair[T, U]{First: first, Second: second}, true
+	}}
+}
+
+func Reduce[T, A any](s Stream[T], initial A, action func(A, T) A) A {
+	result := initial
+	for {
+		value, ok := s.next()
+		if !ok {
+			return result
+		}
+		result = action(result, value)
+	}
+}
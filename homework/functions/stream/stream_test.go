@@ -0,0 +1,201 @@
+package stream
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// go test -v -bench=. -benchmem .
+
+func naturals() Stream[int] {
+	return Iterate(1, func(number int) int { return number + 1 })
+}
+
+func TestSources(t *testing.T) {
+	assert.Nil(t, FromSlice[int](nil).Collect())
+	assert.Equal(t, []int{1, 2, 3}, FromSlice([]int{1, 2, 3}).Collect())
+
+	channel := make(chan string, 3)
+	channel <- "a"
+	channel <- "b"
+	close(channel)
+	assert.Equal(t, []string{"a", "b"}, FromChannel(channel).Collect())
+
+	lhs, rhs := 0, 1
+	fibonacci := Generate(func() (int, bool) {
+		result := lhs
+		lhs, rhs = rhs, lhs+rhs
+		return result, true
+	})
+	assert.Equal(t, []int{0, 1, 1, 2, 3, 5, 8, 13}, fibonacci.Take(8).Collect())
+
+	countdown := 3
+	assert.Equal(t, []int{3, 2, 1}, Generate(func() (int, bool) {
+		countdown--
+		return countdown + 1, countdown >= 0
+	}).Collect())
+}
+
+func TestOperations(t *testing.T) {
+	tests := map[string]struct {
+		stream Stream[int]
+		result []int
+	}{
+		"map": {
+			stream: Map(FromSlice([]int{1, 2, 3}), func(number int) int { return number * number }),
+			result: []int{1, 4, 9},
+		},
+		"filter": {
+			stream: FromSlice([]int{-1, -2, 1, 2}).Filter(func(number int) bool { return number > 0 }),
+			result: []int{1, 2},
+		},
+		"flat map": {
+			stream: FlatMap(FromSlice([]int{1, 0, 2, 3}), func(number int) Stream[int] {
+				return naturals().Take(number)
+			}),
+			result: []int{1, 1, 2, 1, 2, 3},
+		},
+		"take": {
+			stream: naturals().Take(3),
+			result: []int{1, 2, 3},
+		},
+		"take more than available": {
+			stream: FromSlice([]int{1, 2}).Take(5),
+			result: []int{1, 2},
+		},
+		"skip": {
+			stream: naturals().Skip(5).Take(2),
+			result: []int{6, 7},
+		},
+		"skip everything": {
+			stream: FromSlice([]int{1, 2}).Skip(5),
+			result: nil,
+		},
+		"distinct": {
+			stream: Distinct(FromSlice([]int{1, 2, 1, 3, 2, 4})),
+			result: []int{1, 2, 3, 4},
+		},
+		"infinite pipeline": {
+			stream: Map(naturals().Filter(func(number int) bool { return number%3 == 0 }), func(number int) int {
+				return number * 10
+			}).Take(4),
+			result: []int{30, 60, 90, 120},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.result, test.stream.Collect())
+		})
+	}
+}
+
+func TestChunk(t *testing.T) {
+	assert.Equal(t, [][]int{{1, 2}, {3, 4}, {5}}, Chunk(naturals().Take(5), 2).Collect())
+	assert.Nil(t, Chunk(FromSlice([]int{}), 2).Collect())
+	assert.Panics(t, func() { Chunk(naturals(), 0) })
+}
+
+func TestZip(t *testing.T) {
+	names := FromSlice([]string{"a", "b", "c"})
+	pairs := Zip(naturals(), names).Collect()
+	assert.Equal(t, []Pair[int, string]{{1, "a"}, {2, "b"}, {3, "c"}}, pairs)
+}
+
+func TestReduce(t *testing.T) {
+	total := Reduce(naturals().Take(100), 0, func(acc, number int) int { return acc + number })
+	assert.Equal(t, 5050, total)
+
+	text := Reduce(FromSlice([]int{1, 2, 3}), "", func(acc string, number int) string {
+		return acc + strconv.Itoa(number)
+	})
+	assert.Equal(t, "123", text)
+}
+
+func TestShortCircuit(t *testing.T) {
+	pulled := 0
+	source := Map(naturals(), func(number int) int {
+		pulled++
+		return number
+	})
+
+	assert.True(t, source.Any(func(number int) bool { return number == 5 }))
+	assert.Equal(t, 5, pulled)
+
+	var visited []int
+	naturals().ForEach(func(number int) bool {
+		visited = append(visited, number)
+		return number < 3
+	})
+	assert.Equal(t, []int{1, 2, 3}, visited)
+
+	first, ok := naturals().Skip(9).First()
+	assert.True(t, ok)
+	assert.Equal(t, 10, first)
+
+	_, ok = FromSlice([]int{}).First()
+	assert.False(t, ok)
+}
+
+// eager versions from homework/functions, each stage materializes a slice
+func eagerMap(data []int, action func(int) int) []int {
+	result := make([]int, len(data))
+	for idx, value := range data {
+		result[idx] = action(value)
+	}
+	return result
+}
+
+func eagerFilter(data []int, action func(int) bool) []int {
+	var result []int
+	for _, value := range data {
+		if action(value) {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+func eagerReduce(data []int, initial int, action func(int, int) int) int {
+	result := initial
+	for _, value := range data {
+		result = action(result, value)
+	}
+	return result
+}
+
+var (
+	benchmarkData = naturals().Take(10_000).Collect()
+	benchmarkSink int
+)
+
+func square(number int) int   { return number * number }
+func isEven(number int) bool  { return number%2 == 0 }
+func sum(acc, number int) int { return acc + number }
+func isLarge(number int) bool { return number > 1_000 }
+
+func BenchmarkEagerPipeline(b *testing.B) {
+	for range b.N {
+		benchmarkSink = eagerReduce(eagerFilter(eagerMap(benchmarkData, square), isEven), 0, sum)
+	}
+}
+
+func BenchmarkLazyPipeline(b *testing.B) {
+	for range b.N {
+		benchmarkSink = Reduce(Map(FromSlice(benchmarkData), square).Filter(isEven), 0, sum)
+	}
+}
+
+func BenchmarkEagerFirstMatch(b *testing.B) {
+	for range b.N {
+		benchmarkSink = eagerFilter(eagerMap(benchmarkData, square), isLarge)[0]
+	}
+}
+
+func BenchmarkLazyFirstMatch(b *testing.B) {
+	for range b.N {
+		benchmarkSink, _ = Map(FromSlice(benchmarkData), square).Filter(isLarge).First()
+	}
+}
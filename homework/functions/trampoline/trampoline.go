@@ -0,0 +1,92 @@
+package trampoline
+
+// Thunk is a recursive computation described as data. Run evaluates it in a
+// loop, so the goroutine stack does not grow with the recursion depth:
+//
+//	func factorial(number, accumulator int) Thunk[int] {
+//		if number <= 1 {
+//			return Done(accumulator)
+//		}
+//		return Call(func() Thunk[int] { return factorial(number-1, number*accumulator) })
+//	}
+//
+// Tail calls made with Call take constant memory. Calls that still have work
+// to do afterwards are chained with Then, their continuations are kept in a
+// heap allocated stack instead of the goroutine one.
+type Thunk[T any] struct {
+	step *step
+}
+
+type kind uint8
+
+const (
+	kindDone kind = iota
+	kindCall
+	kindThen
+)
+
+// step is the untyped form of a thunk, so thunks of different types can be
+// evaluated by a single loop.
+type step struct {
+	kind   kind
+	value  any
+	call   func() *step
+	source *step
+	then   func(any) *step
+}
+
+// Done is a computation that is already finished.
+func Done[T any](value T) Thunk[T] {
+	return Thunk[T]{step: &step{kind: kindDone, value: value}}
+}
+
+// Call delays fn until Run reaches it, use it for recursive calls.
+func Call[T any](fn func() Thunk[T]) Thunk[T] {
+	return Thunk[T]{step: &step{kind: kindCall, call: func() *step {
+		return fn().step
+	}}}
+}
+
+// Then passes the result of thunk to fn and continues with the returned computation.
+func Then[A, B any](thunk Thunk[A], fn func(A) Thunk[B]) Thunk[B] {
+	return Thunk[B]{step: &step{kind: kindThen, source: thunk.step, then: func(value any) *step {
+		return fn(cast[A](value)).step
+	}}}
+}
+
+func Map[A, B any](thunk Thunk[A], fn func(A) B) Thunk[B] {
+	return Then(thunk, func(value A) Thunk[B] {
+		return Done(fn(value))
+	})
+}
+
+// Run evaluates the computation, a thunk can be run several times.
+func Run[T any](thunk Thunk[T]) T {
+	var continuations []func(any) *step
+	current := thunk.step
+	for {
+		switch current.kind {
+		case kindCall:
+			current = current.call()
+		case kindThen:
+			continuations = append(continuations, current.then)
+			current = current.source
+		default:
+			if len(continuations) == 0 {
+				return cast[T](current.value)
+			}
+
+			last := len(continuations) - 1
+			then := continuations[last]
+			continuations[last] = nil
+			continuations = continuations[:last]
+			current = then(current.value)
+		}
+	}
+}
+
+// cast keeps nil values of interface types, a plain assertion panics on them.
+func cast[T any](value any) T {
+	result, _ := value.(T)
+	return result
+}
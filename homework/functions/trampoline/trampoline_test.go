@@ -0,0 +1,279 @@
+package trampoline
+
+import (
+	"runtime/debug"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// go test -v -race .
+// go test -bench=. -benchmem .
+
+// smallStack limits the goroutine stack, a computation that grows the stack
+// with the recursion depth crashes the test with a stack overflow.
+func smallStack(t *testing.T) {
+	previous := debug.SetMaxStack(1 << 20)
+	t.Cleanup(func() { debug.SetMaxStack(previous) })
+}
+
+func factorial(number, accumulator uint64) Thunk[uint64] {
+	if number <= 1 {
+		return Done(accumulator)
+	}
+	return Call(func() Thunk[uint64] {
+		return factorial(number-1, number*accumulator)
+	})
+}
+
+func plainFibonacci(number int) int {
+	if number < 2 {
+		return number
+	}
+	return plainFibonacci(number-1) + plainFibonacci(number-2)
+}
+
+func fibonacci(number int) Thunk[int] {
+	if number < 2 {
+		return Done(number)
+	}
+	return Then(Call(func() Thunk[int] { return fibonacci(number - 1) }), func(lhs int) Thunk[int] {
+		return Map(Call(func() Thunk[int] { return fibonacci(number - 2) }), func(rhs int) int {
+			return lhs + rhs
+		})
+	})
+}
+
+// iterativeFibonacci is the tail recursive form, the sum wraps around for big numbers.
+func iterativeFibonacci(number int, current, next uint64) Thunk[uint64] {
+	if number == 0 {
+		return Done(current)
+	}
+	return Call(func() Thunk[uint64] {
+		return iterativeFibonacci(number-1, next, current+next)
+	})
+}
+
+func plainAckermann(m, n uint64) uint64 {
+	switch {
+	case m == 0:
+		return n + 1
+	case n == 0:
+		return plainAckermann(m-1, 1)
+	}
+	return plainAckermann(m-1, plainAckermann(m, n-1))
+}
+
+func ackermann(m, n uint64) Thunk[uint64] {
+	switch {
+	case m == 0:
+		return Done(n + 1)
+	case n == 0:
+		return Call(func() Thunk[uint64] { return ackermann(m-1, 1) })
+	}
+	return Then(Call(func() Thunk[uint64] { return ackermann(m, n-1) }), func(inner uint64) Thunk[uint64] {
+		return ackermann(m-1, inner)
+	})
+}
+
+type node struct {
+	left, right *node
+	key         int
+}
+
+func plainWalk(curr *node, action func(int)) {
+	if curr == nil {
+		return
+	}
+	plainWalk(curr.left, action)
+	action(curr.key)
+	plainWalk(curr.right, action)
+}
+
+// walk is the in order traversal of OrderedMap.inOrderAction from homework/maps.
+func walk(curr *node, action func(int)) Thunk[struct{}] {
+	if curr == nil {
+		return Done(struct{}{})
+	}
+	return Then(Call(func() Thunk[struct{}] { return walk(curr.left, action) }), func(struct{}) Thunk[struct{}] {
+		action(curr.key)
+		return walk(curr.right, action)
+	})
+}
+
+// chain builds a degenerate tree, every node is the left child of the next one.
+func chain(size int) *node {
+	var root *node
+	for key := range size {
+		root = &node{left: root, key: key}
+	}
+	return root
+}
+
+func balanced(keys []int) *node {
+	if len(keys) == 0 {
+		return nil
+	}
+	middle := len(keys) / 2
+	return &node{left: balanced(keys[:middle]), right: balanced(keys[middle+1:]), key: keys[middle]}
+}
+
+type object struct {
+	next *object
+}
+
+// mark follows the references like the visit function of homework/garbage_collector.
+func mark(curr *object, seen map[*object]struct{}) Thunk[int] {
+	if _, ok := seen[curr]; curr == nil || ok {
+		return Done(len(seen))
+	}
+	seen[curr] = struct{}{}
+	return Call(func() Thunk[int] { return mark(curr.next, seen) })
+}
+
+func TestFactorial(t *testing.T) {
+	tests := map[string]struct {
+		number uint64
+		result uint64
+	}{
+		"zero": {number: 0, result: 1},
+		"one":  {number: 1, result: 1},
+		"five": {number: 5, result: 120},
+		"ten":  {number: 10, result: 3628800},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.result, Run(factorial(test.number, 1)))
+		})
+	}
+}
+
+func TestFibonacci(t *testing.T) {
+	for number := range 20 {
+		assert.Equal(t, plainFibonacci(number), Run(fibonacci(number)))
+	}
+
+	smallStack(t)
+	var current, next uint64 = 0, 1
+	for range 5_000_000 {
+		current, next = next, current+next
+	}
+	assert.Equal(t, current, Run(iterativeFibonacci(5_000_000, 0, 1)))
+}
+
+func TestAckermann(t *testing.T) {
+	tests := map[string]struct {
+		m, n uint64
+	}{
+		"zero":  {m: 0, n: 0},
+		"one":   {m: 1, n: 5},
+		"two":   {m: 2, n: 3},
+		"three": {m: 3, n: 6},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, plainAckermann(test.m, test.n), Run(ackermann(test.m, test.n)))
+		})
+	}
+
+	// the recursion goes n levels deep
+	smallStack(t)
+	assert.Equal(t, uint64(3_000_002), Run(ackermann(1, 3_000_000)))
+}
+
+func TestTreeWalk(t *testing.T) {
+	keys := []int{1, 2, 3, 4, 5, 6, 7, 8, 9}
+	var visited []int
+	Run(walk(balanced(keys), func(key int) { visited = append(visited, key) }))
+	assert.Equal(t, keys, visited)
+
+	smallStack(t)
+	const depth = 2_000_000
+	count, ordered := 0, true
+	Run(walk(chain(depth), func(key int) {
+		ordered = ordered && key == count
+		count++
+	}))
+	assert.Equal(t, depth, count)
+	assert.True(t, ordered)
+}
+
+func TestMark(t *testing.T) {
+	const size = 3_000_000
+	objects := make([]object, size)
+	for idx := range size - 1 {
+		objects[idx].next = &objects[idx+1]
+	}
+	// the cycle is visited once
+	objects[size-1].next = &objects[size/2]
+
+	smallStack(t)
+	assert.Equal(t, size, Run(mark(&objects[0], make(map[*object]struct{}))))
+	assert.Zero(t, Run(mark(nil, make(map[*object]struct{}))))
+}
+
+func TestRunTwice(t *testing.T) {
+	calls := 0
+	thunk := Map(Call(func() Thunk[int] {
+		calls++
+		return Done(calls)
+	}), func(value int) int { return value * 10 })
+
+	assert.Equal(t, 10, Run(thunk))
+	assert.Equal(t, 20, Run(thunk))
+
+	// nil values of interface types are kept
+	assert.Nil(t, Run(Then(Done[error](nil), func(err error) Thunk[error] { return Done(err) })))
+}
+
+func BenchmarkPlainFibonacci(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = plainFibonacci(20)
+	}
+}
+
+func BenchmarkTrampolineFibonacci(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = Run(fibonacci(20))
+	}
+}
+
+func BenchmarkPlainAckermann(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = plainAckermann(2, 100)
+	}
+}
+
+func BenchmarkTrampolineAckermann(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = Run(ackermann(2, 100))
+	}
+}
+
+func BenchmarkPlainTreeWalk(b *testing.B) {
+	keys := make([]int, 1<<12)
+	for idx := range keys {
+		keys[idx] = idx
+	}
+	root := balanced(keys)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		plainWalk(root, func(int) {})
+	}
+}
+
+func BenchmarkTrampolineTreeWalk(b *testing.B) {
+	keys := make([]int, 1<<12)
+	for idx := range keys {
+		keys[idx] = idx
+	}
+	root := balanced(keys)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Run(walk(root, func(int) {}))
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"testing"
 	"unsafe"
 	"github.com/stretchr/testify/assert"
+
+	"golang_course/homework/functions/trampoline"
 )
 
 // go test -v homework_test.go
@@ -24,14 +26,20 @@ func (s Set) Add(p uintptr) {
 var ptrs = make(Set)
 
 func visit(p uintptr) {
+	trampoline.Run(follow(p))
+}
+
+// follow is the tail recursive part of visit, long pointer chains do not grow the stack.
+func follow(p uintptr) trampoline.Thunk[struct{}] {
 	if p == 0x00 || ptrs.WasSeen(p) {
-		return
+		return trampoline.Done(struct{}{})
 	}
-	
+
 	ptrs.Add(p)
 	nestedP := (*uintptr)(unsafe.Pointer(p))
-	visit(*nestedP)
-
+	return trampoline.Call(func() trampoline.Thunk[struct{}] {
+		return follow(*nestedP)
+	})
 }
 
 func Trace(stacks [][]uintptr) []uintptr {
@@ -1,12 +1,11 @@
 package main
 
 import (
-	"fmt"
-	"reflect"
-	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"golang_course/homework/generics_and_reflection/properties"
 )
 
 // go test -v homework_test.go
@@ -18,23 +17,14 @@ type Person struct {
 	Married bool   `properties:"married"`
 }
 
+// Serialize writes the person with properties.Marshal, all fields of Person
+// are supported so it can not fail.
 func Serialize(person Person) string {
-	t := reflect.TypeOf(person)
-	v := reflect.ValueOf(person)
-	res := make([]string, 0, t.NumField())
-	for i := range t.NumField() {
-		tag, exists := t.Field(i).Tag.Lookup("properties")
-		if !exists {
-			continue
-		}
-		if v.Field(i).IsZero() && strings.Contains(tag, "omitempty") {
-			continue
-		}
-		tagName := strings.ReplaceAll(tag, ",omitempty", "")
-		tagName = strings.ReplaceAll(tagName, "omitempty,", "")
-		res = append(res, fmt.Sprintf("%s=%v", tagName, v.Field(i)))
+	data, err := properties.Marshal(person)
+	if err != nil {
+		panic(err)
 	}
-	return strings.Join(res, "\n")
+	return string(data)
 }
 
 func TestSerialization(t *testing.T) {
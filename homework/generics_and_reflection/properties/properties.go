@@ -0,0 +1,279 @@
+package properties
+
+import (
+	"cmp"
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrUnsupportedType = errors.New("unsupported type")
+	ErrPointerCycle    = errors.New("pointer cycle")
+)
+
+// MarshalError reports the key of the value that can not be written.
+type MarshalError struct {
+	Key  string
+	Type reflect.Type
+	Err  error
+}
+
+func (e *MarshalError) Error() string {
+	if e.Key == "" {
+		return fmt.Sprintf("properties: %v %v", e.Err, e.Type)
+	}
+	return fmt.Sprintf("properties: %v %v at %q", e.Err, e.Type, e.Key)
+}
+
+func (e *MarshalError) Unwrap() error {
+	return e.Err
+}
+
+var (
+	durationType      = reflect.TypeFor[time.Duration]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// Marshal writes the fields of a struct with the properties tag as key=value
+// lines, fields without the tag are skipped:
+//
+//	type Person struct {
+//		Name    string            `properties:"name"`
+//		Address *Address          `properties:"omitempty,address"`
+//		Phones  []string          `properties:"phones"`
+//		Labels  map[string]string `properties:"labels"`
+//	}
+//
+// Nested structs use dotted keys (address.city), slices and maps add the index
+// or the map key (phones.0, labels.team). Map keys are sorted, nil pointers
+// are written as empty values. time.Duration is written as a string like
+// 1h30m0s, time.Time and other encoding.TextMarshaler types as their text.
+func Marshal(value any) ([]byte, error) {
+	kind := reflect.TypeOf(value)
+	for kind != nil && kind.Kind() == reflect.Pointer {
+		kind = kind.Elem()
+	}
+	if kind == nil || kind.Kind() != reflect.Struct {
+		return nil, &MarshalError{Type: reflect.TypeOf(value), Err: ErrUnsupportedType}
+	}
+
+	encoder := encoder{visited: make(map[reference]bool)}
+	current := reflect.ValueOf(value)
+	for current.Kind() == reflect.Pointer {
+		if current.IsNil() {
+			return []byte{}, nil
+		}
+		encoder.visited[reference{pointer: current.Pointer(), kind: current.Type()}] = true
+		current = current.Elem()
+	}
+
+	if err := encoder.fields("", current); err != nil {
+		return nil, err
+	}
+	return []byte(strings.Join(encoder.lines, "\n")), nil
+}
+
+type encoder struct {
+	lines []string
+	// pointers on the path to the current value, they are allowed to repeat elsewhere
+	visited map[reference]bool
+}
+
+// reference keeps the type too, a struct and its first field share the address.
+type reference struct {
+	pointer uintptr
+	kind    reflect.Type
+}
+
+// parseTag accepts the name and the omitempty option in any order.
+func parseTag(tag string) (name string, omitEmpty bool) {
+	for _, part := range strings.Split(tag, ",") {
+		if part == "omitempty" {
+			omitEmpty = true
+		} else {
+			name = part
+		}
+	}
+	return name, omitEmpty
+}
+
+func join(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+func (e *encoder) fields(prefix string, value reflect.Value) error {
+	for idx := range value.NumField() {
+		field := value.Type().Field(idx)
+		tag, exists := field.Tag.Lookup("properties")
+		if !exists || tag == "-" || !field.IsExported() {
+			continue
+		}
+
+		name, omitEmpty := parseTag(tag)
+		fieldValue := value.Field(idx)
+		if omitEmpty && fieldValue.IsZero() {
+			continue
+		}
+		if err := e.value(join(prefix, name), fieldValue); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *encoder) value(key string, value reflect.Value) error {
+	switch value.Kind() {
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return &MarshalError{Key: key, Type: value.Type(), Err: ErrUnsupportedType}
+	}
+
+	if value.Type() == durationType {
+		e.write(key, time.Duration(value.Int()).String())
+		return nil
+	}
+	if value.Type().Implements(textMarshalerType) && !isNil(value) {
+		return e.text(key, value)
+	}
+	if value.CanAddr() && reflect.PointerTo(value.Type()).Implements(textMarshalerType) {
+		return e.text(key, value.Addr())
+	}
+
+	switch value.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		return e.pointer(key, value)
+	case reflect.Struct:
+		return e.fields(key, value)
+	case reflect.Slice, reflect.Array:
+		for idx := range value.Len() {
+			if err := e.value(join(key, strconv.Itoa(idx)), value.Index(idx)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		return e.mapEntries(key, value)
+	}
+
+	text, err := scalar(value)
+	if err != nil {
+		return &MarshalError{Key: key, Type: value.Type(), Err: err}
+	}
+	e.write(key, text)
+	return nil
+}
+
+func isNil(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		return value.IsNil()
+	}
+	return false
+}
+
+func (e *encoder) text(key string, value reflect.Value) error {
+	text, err := value.Interface().(encoding.TextMarshaler).MarshalText()
+	if err != nil {
+		return &MarshalError{Key: key, Type: value.Type(), Err: err}
+	}
+	e.write(key, string(text))
+	return nil
+}
+
+func (e *encoder) pointer(key string, value reflect.Value) error {
+	if value.IsNil() {
+		e.write(key, "")
+		return nil
+	}
+	if value.Kind() == reflect.Interface {
+		return e.value(key, value.Elem())
+	}
+
+	seen := reference{pointer: value.Pointer(), kind: value.Type()}
+	if e.visited[seen] {
+		return &MarshalError{Key: key, Type: value.Type(), Err: ErrPointerCycle}
+	}
+	e.visited[seen] = true
+	defer delete(e.visited, seen)
+
+	return e.value(key, value.Elem())
+}
+
+func (e *encoder) mapEntries(key string, value reflect.Value) error {
+	type entry struct {
+		key   reflect.Value
+		text  string
+		value reflect.Value
+	}
+
+	entries := make([]entry, 0, value.Len())
+	for iterator := value.MapRange(); iterator.Next(); {
+		text, err := mapKey(iterator.Key())
+		if err != nil {
+			return &MarshalError{Key: key, Type: value.Type(), Err: err}
+		}
+		entries = append(entries, entry{key: iterator.Key(), text: text, value: iterator.Value()})
+	}
+
+	// numeric keys are ordered by value, so 2 goes before 10
+	slices.SortFunc(entries, func(lhs, rhs entry) int {
+		switch lhs.key.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return cmp.Compare(lhs.key.Int(), rhs.key.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return cmp.Compare(lhs.key.Uint(), rhs.key.Uint())
+		case reflect.Float32, reflect.Float64:
+			return cmp.Compare(lhs.key.Float(), rhs.key.Float())
+		}
+		return strings.Compare(lhs.text, rhs.text)
+	})
+
+	for _, entry := range entries {
+		if err := e.value(join(key, entry.text), entry.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func mapKey(key reflect.Value) (string, error) {
+	if marshaler, ok := key.Interface().(encoding.TextMarshaler); ok {
+		text, err := marshaler.MarshalText()
+		return string(text), err
+	}
+	return scalar(key)
+}
+
+func scalar(value reflect.Value) (string, error) {
+	switch value.Kind() {
+	case reflect.String:
+		return value.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(value.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(value.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(value.Float(), 'g', -1, value.Type().Bits()), nil
+	case reflect.Complex64, reflect.Complex128:
+		return strconv.FormatComplex(value.Complex(), 'g', -1, value.Type().Bits()), nil
+	}
+	return "", ErrUnsupportedType
+}
+
+// lineEscaper keeps a value on its line, backslashes are escaped so the
+// escapes can be read back.
+var lineEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, "\r", `\r`)
+
+func (e *encoder) write(key, value string) {
+	e.lines = append(e.lines, key+"="+lineEscaper.Replace(value))
+}
@@ -0,0 +1,212 @@
+package properties
+
+import (
+	"encoding"
+	"errors"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// go test -v .
+
+type Address struct {
+	City   string `properties:"city"`
+	Street string `properties:"omitempty,street"`
+}
+
+type Person struct {
+	Name    string   `properties:"name"`
+	Address *Address `properties:"omitempty,address"`
+	Age     int      `properties:"age"`
+	Married bool     `properties:"married"`
+	comment string   `properties:"comment"`
+	Ignored string
+}
+
+type Level int
+
+func (l Level) MarshalText() ([]byte, error) {
+	switch l {
+	case 0:
+		return []byte("debug"), nil
+	case 1:
+		return []byte("info"), nil
+	}
+	return nil, errors.New("unknown level")
+}
+
+type Server struct {
+	Host     netip.Addr        `properties:"host"`
+	Timeout  time.Duration     `properties:"timeout"`
+	Started  time.Time         `properties:"started"`
+	Level    Level             `properties:"level"`
+	Tags     []string          `properties:"tags"`
+	Ports    [2]uint16         `properties:"ports"`
+	Limits   map[int]float64   `properties:"limits"`
+	Backends []Address         `properties:"backends"`
+	Labels   map[string]string `properties:"omitempty,labels"`
+	Extra    any               `properties:"extra"`
+}
+
+func TestMarshal(t *testing.T) {
+	tests := map[string]struct {
+		value  any
+		result string
+	}{
+		"empty fields": {
+			value:  Person{},
+			result: "name=\nage=0\nmarried=false",
+		},
+		"nested struct": {
+			value:  Person{Name: "John Doe", Address: &Address{City: "Paris"}, Age: 30},
+			result: "name=John Doe\naddress.city=Paris\nage=30\nmarried=false",
+		},
+		"pointer to struct": {
+			value:  &Address{City: "Berlin", Street: "Unter den Linden"},
+			result: "city=Berlin\nstreet=Unter den Linden",
+		},
+		"nil pointer to struct": {
+			value:  (*Address)(nil),
+			result: "",
+		},
+		"line breaks": {
+			value:  Address{City: "first\nsecond\\"},
+			result: `city=first\nsecond\\`,
+		},
+		"all kinds": {
+			value: Server{
+				Host:     netip.MustParseAddr("10.0.0.1"),
+				Timeout:  90 * time.Second,
+				Started:  time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC),
+				Level:    1,
+				Tags:     []string{"primary", "eu"},
+				Ports:    [2]uint16{80, 443},
+				Limits:   map[int]float64{10: 0.5, 2: 1.25},
+				Backends: []Address{{City: "Paris"}, {City: "Rome", Street: "Via Appia"}},
+				Extra:    &Address{City: "Oslo"},
+			},
+			result: strings.Join([]string{
+				"host=10.0.0.1",
+				"timeout=1m30s",
+				"started=2024-05-01T12:30:00Z",
+				"level=info",
+				"tags.0=primary",
+				"tags.1=eu",
+				"ports.0=80",
+				"ports.1=443",
+				"limits.2=1.25",
+				"limits.10=0.5",
+				"backends.0.city=Paris",
+				"backends.1.city=Rome",
+				"backends.1.street=Via Appia",
+				"extra.city=Oslo",
+			}, "\n"),
+		},
+		"sorted map keys": {
+			value: struct {
+				Labels map[string]string `properties:"labels"`
+				Empty  []int             `properties:"empty"`
+				Nil    *int              `properties:"nil"`
+			}{Labels: map[string]string{"team": "core", "env": "prod"}},
+			result: "labels.env=prod\nlabels.team=core\nnil=",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := Marshal(test.value)
+			require.NoError(t, err)
+			assert.Equal(t, test.result, string(result))
+		})
+	}
+}
+
+func TestMarshalErrors(t *testing.T) {
+	type node struct {
+		Name string `properties:"name"`
+		Next *node  `properties:"next"`
+	}
+	cycle := &node{Name: "first", Next: &node{Name: "second"}}
+	cycle.Next.Next = cycle
+
+	tests := map[string]struct {
+		value any
+		err   error
+		text  string
+	}{
+		"channel": {
+			value: struct {
+				Events chan int `properties:"events"`
+			}{},
+			err:  ErrUnsupportedType,
+			text: `properties: unsupported type chan int at "events"`,
+		},
+		"function": {
+			value: struct {
+				Nested struct {
+					Callback func() `properties:"callback"`
+				} `properties:"nested"`
+			}{},
+			err:  ErrUnsupportedType,
+			text: `properties: unsupported type func() at "nested.callback"`,
+		},
+		"not a struct": {
+			value: 42,
+			err:   ErrUnsupportedType,
+			text:  "properties: unsupported type int",
+		},
+		"nil": {
+			value: nil,
+			err:   ErrUnsupportedType,
+			text:  "properties: unsupported type <nil>",
+		},
+		"pointer cycle": {
+			value: cycle,
+			err:   ErrPointerCycle,
+			text:  `properties: pointer cycle *properties.node at "next.next"`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Marshal(test.value)
+			assert.ErrorIs(t, err, test.err)
+			assert.EqualError(t, err, test.text)
+		})
+	}
+
+	// errors of text marshalers keep the key
+	_, err := Marshal(Server{Level: 5})
+	var marshalErr *MarshalError
+	require.ErrorAs(t, err, &marshalErr)
+	assert.Equal(t, "level", marshalErr.Key)
+	assert.Implements(t, (*encoding.TextMarshaler)(nil), Level(0))
+}
+
+func TestSharedPointers(t *testing.T) {
+	shared := &Address{City: "Paris"}
+	result, err := Marshal(struct {
+		Home *Address `properties:"home"`
+		Work *Address `properties:"work"`
+	}{Home: shared, Work: shared})
+
+	require.NoError(t, err)
+	assert.Equal(t, "home.city=Paris\nwork.city=Paris", string(result))
+
+	// the first field has the address of the struct, it is not a cycle
+	type Office struct {
+		Address Address  `properties:"address"`
+		Mail    *Address `properties:"mail"`
+	}
+	office := &Office{Address: Address{City: "Rome"}}
+	office.Mail = &office.Address
+
+	result, err = Marshal(office)
+	require.NoError(t, err)
+	assert.Equal(t, "address.city=Rome\nmail.city=Rome", string(result))
+}
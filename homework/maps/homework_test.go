@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"golang_course/homework/functions/trampoline"
 )
 
 type TNode struct {
@@ -55,12 +57,22 @@ func (m *OrderedMap) ForEach(action func(int, int)) {
 }
 
 func (m *OrderedMap) inOrderAction(curr *TNode, action func(int, int)) {
+	trampoline.Run(m.inOrder(curr, action))
+}
+
+// inOrder walks the tree in constant stack space, see the trampoline package.
+func (m *OrderedMap) inOrder(curr *TNode, action func(int, int)) trampoline.Thunk[struct{}] {
 	if curr == nil {
-		return
+		return trampoline.Done(struct{}{})
 	}
-	m.inOrderAction(curr.left, action)
-	action(curr.key, curr.value)
-	m.inOrderAction(curr.right, action)
+
+	left := trampoline.Call(func() trampoline.Thunk[struct{}] {
+		return m.inOrder(curr.left, action)
+	})
+	return trampoline.Then(left, func(struct{}) trampoline.Thunk[struct{}] {
+		action(curr.key, curr.value)
+		return m.inOrder(curr.right, action)
+	})
 }
 
 func (m *OrderedMap) delete(curr *TNode, key int) *TNode {